@@ -3,6 +3,8 @@ package elastic
 import (
 	"github.com/KingSolvewer/elasticsearch-query-builder/aggs"
 	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"github.com/KingSolvewer/elasticsearch-query-builder/geo"
+	"strings"
 )
 
 type SubAggFunc func(b *Builder)
@@ -10,6 +12,8 @@ type SubAggFunc func(b *Builder)
 type Aggregation struct {
 	Params  esearch.Aggregator
 	SubAggs []SubAggFunc
+
+	subAggBuilder *Builder // 注册时执行 SubAggs 得到的 Builder, 生成语句和校验 buckets_path 时复用, 闭包函数只执行一次
 }
 
 func (b *Builder) GroupBy(field string, param aggs.TermsParam, subAggFuncSet ...SubAggFunc) *Builder {
@@ -23,7 +27,71 @@ func (b *Builder) GroupBy(field string, param aggs.TermsParam, subAggFuncSet ...
 	return b.Aggs(field+esearch.Terms, termsAgg, subAggFuncSet...)
 }
 
+// MultiGroupBy 按照多个字段的组合进行分组聚合, 桶的 Keys 为各字段的值, KeyAsString 为使用 | 连接的字符串
+func (b *Builder) MultiGroupBy(name string, terms []aggs.MultiTermsField, param aggs.MultiTermsParam, subAggFuncSet ...SubAggFunc) *Builder {
+	if len(terms) < 2 {
+		return b
+	}
+
+	multiTermsAggs := &aggs.MultiTermsAggs{
+		MultiTerms: aggs.MultiTerms{
+			Terms:           terms,
+			MultiTermsParam: param,
+		},
+	}
+
+	return b.Aggs(name+esearch.MultiTerms, multiTermsAggs, subAggFuncSet...)
+}
+
+// SignificantTerms backgroundFilter 为空时, 使用索引中的全部文档作为背景集合
+func (b *Builder) SignificantTerms(field string, param aggs.SignificantTermsParam, backgroundFilter NestWhereFunc, subAggFuncSet ...SubAggFunc) *Builder {
+	significantTermsAggs := &aggs.SignificantTermsAggs{
+		SignificantTerms: aggs.SignificantTerms{
+			Field:                 field,
+			SignificantTermsParam: param,
+		},
+	}
+
+	if backgroundFilter != nil {
+		significantTermsAggs.BackgroundFilter = b.subQuery(backgroundFilter)
+	}
+
+	return b.Aggs(field+esearch.SignificantTerms, significantTermsAggs, subAggFuncSet...)
+}
+
+// SignificantText 用于 text 类型的字段, 不需要开启 fielddata
+func (b *Builder) SignificantText(field string, param aggs.SignificantTextParam, backgroundFilter NestWhereFunc, subAggFuncSet ...SubAggFunc) *Builder {
+	significantTextAggs := &aggs.SignificantTextAggs{
+		SignificantText: aggs.SignificantText{
+			Field:                field,
+			SignificantTextParam: param,
+		},
+	}
+
+	if backgroundFilter != nil {
+		significantTextAggs.BackgroundFilter = b.subQuery(backgroundFilter)
+	}
+
+	return b.Aggs(field+esearch.SignificantText, significantTextAggs, subAggFuncSet...)
+}
+
+func (b *Builder) RareTerms(field string, param aggs.RareTermsParam, subAggFuncSet ...SubAggFunc) *Builder {
+	rareTermsAggs := &aggs.RareTermsAggs{
+		RareTerms: aggs.RareTerms{
+			Field:          field,
+			RareTermsParam: param,
+		},
+	}
+
+	return b.Aggs(field+esearch.RareTerms, rareTermsAggs, subAggFuncSet...)
+}
+
+// Histogram 数值直方图, param 中只能使用 Interval, CalendarInterval, FixedInterval, TimeZone 只用于 DateGroupBy, 设置时会 panic
 func (b *Builder) Histogram(field string, param aggs.HistogramParam, subAggFuncSet ...SubAggFunc) *Builder {
+	if param.CalendarInterval != "" || param.FixedInterval != "" || param.TimeZone != "" {
+		panic("Histogram Aggregation setting is fault! calendar_interval, fixed_interval and time_zone are only used in date_histogram")
+	}
+
 	histogram := &aggs.HistogramAggs{
 		Histogram: aggs.Histogram{
 			Field:          field,
@@ -34,7 +102,12 @@ func (b *Builder) Histogram(field string, param aggs.HistogramParam, subAggFuncS
 	return b.Aggs(field+esearch.Histogram, histogram, subAggFuncSet...)
 }
 
+// DateGroupBy param 中的 Interval, CalendarInterval, FixedInterval 只能设置一个, 同时设置多个或者时间间隔不符合格式时 panic
 func (b *Builder) DateGroupBy(field string, param aggs.HistogramParam, subAggFuncSet ...SubAggFunc) *Builder {
+	if !checkDateInterval(&param) {
+		panic("DateGroupBy Aggregation setting is fault! invalid interval")
+	}
+
 	histogram := &aggs.DateHistogramAggs{
 		Histogram: aggs.Histogram{
 			Field:          field,
@@ -45,6 +118,38 @@ func (b *Builder) DateGroupBy(field string, param aggs.HistogramParam, subAggFun
 	return b.Aggs(field+esearch.DateHistogram, histogram, subAggFuncSet...)
 }
 
+// AutoDateGroupBy 由 es 选择时间间隔, 使桶的数量不超过 buckets, minimumInterval 可选 year, month, day, hour, minute, second, 不区分大小写, 为空时不限制
+func (b *Builder) AutoDateGroupBy(field string, buckets int, minimumInterval string, param aggs.AutoDateHistogramParam, subAggFuncSet ...SubAggFunc) *Builder {
+	minimumInterval = strings.ToLower(minimumInterval)
+	if minimumInterval != "" && !autoDateMinimumIntervals[minimumInterval] {
+		panic("AutoDateGroupBy Aggregation setting is fault! invalid minimumInterval")
+	}
+
+	autoDateHistogram := &aggs.AutoDateHistogramAggs{
+		AutoDateHistogram: aggs.AutoDateHistogram{
+			Field:                  field,
+			Buckets:                buckets,
+			MinimumInterval:        minimumInterval,
+			AutoDateHistogramParam: param,
+		},
+	}
+
+	return b.Aggs(field+esearch.AutoDateHistogram, autoDateHistogram, subAggFuncSet...)
+}
+
+// VariableWidthHistogram 根据数据的分布动态划分 buckets 个宽度不同的桶
+func (b *Builder) VariableWidthHistogram(field string, buckets int, param aggs.VariableWidthHistogramParam, subAggFuncSet ...SubAggFunc) *Builder {
+	variableWidthHistogram := &aggs.VariableWidthHistogramAggs{
+		VariableWidthHistogram: aggs.VariableWidthHistogram{
+			Field:                       field,
+			Buckets:                     buckets,
+			VariableWidthHistogramParam: param,
+		},
+	}
+
+	return b.Aggs(field+esearch.VariableWidthHistogram, variableWidthHistogram, subAggFuncSet...)
+}
+
 func (b *Builder) Range(field string, param aggs.RangeParam, subAggFuncSet ...SubAggFunc) *Builder {
 	if !checkAggsRangeType(param.Ranges) {
 		panic("Range Aggregation setting is fault!")
@@ -74,17 +179,34 @@ func (b *Builder) DateRange(field string, param aggs.RangeParam, subAggFuncSet .
 	return b.Aggs(field+esearch.DateRange, rangeAggs, subAggFuncSet...)
 }
 
-func (b *Builder) AggsFilter(field string, fn NestWhereFunc, subAggFuncs ...SubAggFunc) *Builder {
+// Composite name 为聚合名称, 每个数据源都必须设置 Name, after 为上一页结果中的 after_key, 第一页传 nil. 翻页遍历可以使用 CompositeEach
+func (b *Builder) Composite(name string, sources []aggs.CompositeSource, size int, after map[string]any, subAggFuncSet ...SubAggFunc) *Builder {
+	if len(sources) == 0 {
+		return b
+	}
 
-	if fn != nil {
-		newBuilder := NewBuilder()
-		fn(newBuilder)
+	for _, source := range sources {
+		if source == nil || source.SourceName() == "" {
+			panic("Composite Aggregation setting is fault! source name is empty")
+		}
+	}
 
-		query := make(esearch.Query)
-		query["bool"] = newBuilder.componentWhere()
+	composite := &aggs.CompositeAggs{
+		Composite: aggs.Composite{
+			Size:    size,
+			Sources: sources,
+			After:   after,
+		},
+	}
+
+	return b.Aggs(name+esearch.Composite, composite, subAggFuncSet...)
+}
+
+func (b *Builder) AggsFilter(field string, fn NestWhereFunc, subAggFuncs ...SubAggFunc) *Builder {
 
+	if fn != nil {
 		filterAggs := &aggs.FilterAggs{
-			Filter: query,
+			Filter: b.subQuery(fn),
 		}
 
 		return b.Aggs(field+esearch.AggsFilter, filterAggs, subAggFuncs...)
@@ -93,11 +215,198 @@ func (b *Builder) AggsFilter(field string, fn NestWhereFunc, subAggFuncs ...SubA
 	return b
 }
 
+// GeoHashGrid precision 为 1 到 12 的整数或者距离, 例如 1km, 为 nil 时 es 默认为 5
+func (b *Builder) GeoHashGrid(field string, precision any, param aggs.GeoGridParam, subAggFuncSet ...SubAggFunc) *Builder {
+	geoHashGridAggs := &aggs.GeoHashGridAggs{
+		GeoGrid: aggs.GeoGrid{
+			Field:        field,
+			Precision:    precision,
+			GeoGridParam: param,
+		},
+	}
+
+	return b.Aggs(field+esearch.GeoHashGrid, geoHashGridAggs, subAggFuncSet...)
+}
+
+// GeoTileGrid precision 为 0 到 29 的缩放级别, 超出范围时 panic
+func (b *Builder) GeoTileGrid(field string, precision int, param aggs.GeoGridParam, subAggFuncSet ...SubAggFunc) *Builder {
+	if precision < 0 || precision > 29 {
+		panic("GeoTileGrid Aggregation setting is fault! precision must be in 0..29")
+	}
+
+	geoTileGridAggs := &aggs.GeoTileGridAggs{
+		GeoGrid: aggs.GeoGrid{
+			Field:        field,
+			Precision:    precision,
+			GeoGridParam: param,
+		},
+	}
+
+	return b.Aggs(field+esearch.GeoTileGrid, geoTileGridAggs, subAggFuncSet...)
+}
+
+// GeoDistanceRange 按照与 origin 的距离范围分组, 结果在 Range 中. origin 和 param.Ranges 必须设置
+func (b *Builder) GeoDistanceRange(field string, origin geo.Point, param aggs.GeoDistanceParam, subAggFuncSet ...SubAggFunc) *Builder {
+	if origin == nil || len(param.Ranges) == 0 {
+		panic("GeoDistanceRange Aggregation setting is fault! origin and ranges must be set")
+	}
+
+	geoDistanceAggs := &aggs.GeoDistanceAggs{
+		GeoDistance: aggs.GeoDistance{
+			Field:            field,
+			Origin:           origin,
+			GeoDistanceParam: param,
+		},
+	}
+
+	return b.Aggs(field+esearch.GeoDistance, geoDistanceAggs, subAggFuncSet...)
+}
+
+func (b *Builder) GeoBounds(field string, wrapLongitude *bool) *Builder {
+	geoBoundsAggs := &aggs.GeoBoundsAggs{
+		GeoBounds: aggs.GeoBounds{
+			Field:         field,
+			WrapLongitude: wrapLongitude,
+		},
+	}
+
+	return b.Aggs(field+esearch.GeoBounds, geoBoundsAggs)
+}
+
+func (b *Builder) GeoCentroid(field string) *Builder {
+	geoCentroidAggs := &aggs.GeoCentroidAggs{
+		GeoCentroid: aggs.GeoCentroid{
+			Field: field,
+		},
+	}
+
+	return b.Aggs(field+esearch.GeoCentroid, geoCentroidAggs)
+}
+
+// AggsFilters filters 是多桶聚合, filters 的键为桶名称, 值为构建过滤条件的闭包函数
+func (b *Builder) AggsFilters(name string, filters map[string]NestWhereFunc, param aggs.FiltersParam, subAggFuncs ...SubAggFunc) *Builder {
+	queries := b.aggsFilterQueries(filters)
+	if len(queries) == 0 {
+		return b
+	}
+
+	filtersAggs := &aggs.FiltersAggs{
+		Filters: aggs.Filters{
+			Filters:      queries,
+			FiltersParam: param,
+		},
+	}
+
+	return b.Aggs(name+esearch.Filters, filtersAggs, subAggFuncs...)
+}
+
+// AdjacencyMatrix 统计每个过滤条件以及两两过滤条件交集的文档数量, 交集桶的名称为 名称A + separator + 名称B
+func (b *Builder) AdjacencyMatrix(name string, filters map[string]NestWhereFunc, separator string, subAggFuncs ...SubAggFunc) *Builder {
+	queries := b.aggsFilterQueries(filters)
+	if len(queries) == 0 {
+		return b
+	}
+
+	adjacencyMatrixAggs := &aggs.AdjacencyMatrixAggs{
+		AdjacencyMatrix: aggs.AdjacencyMatrix{
+			Filters:   queries,
+			Separator: separator,
+		},
+	}
+
+	return b.Aggs(name+esearch.AdjacencyMatrix, adjacencyMatrixAggs, subAggFuncs...)
+}
+
+func (b *Builder) aggsFilterQueries(filters map[string]NestWhereFunc) map[string]esearch.Query {
+	queries := make(map[string]esearch.Query)
+	for name, fn := range filters {
+		if fn != nil {
+			queries[name] = b.subQuery(fn)
+		}
+	}
+
+	return queries
+}
+
+// NestedAggs 对 path 下的嵌套文档进行子聚合, 聚合名称为 path + esearch.Nested
+func (b *Builder) NestedAggs(path string, subAggFuncSet ...SubAggFunc) *Builder {
+	nestedAggs := &aggs.NestedAggs{
+		Nested: aggs.Nested{
+			Path: path,
+		},
+	}
+
+	return b.Aggs(path+esearch.Nested, nestedAggs, subAggFuncSet...)
+}
+
+// ReverseNested 在 NestedAggs 的子聚合中使用, 从嵌套文档回到上层文档进行聚合. path 为空时回到根文档, 聚合名称为 root + esearch.ReverseNested
+func (b *Builder) ReverseNested(path string, subAggFuncSet ...SubAggFunc) *Builder {
+	reverseNestedAggs := &aggs.ReverseNestedAggs{
+		ReverseNested: aggs.ReverseNested{
+			Path: path,
+		},
+	}
+
+	name := path
+	if name == "" {
+		name = "root"
+	}
+
+	return b.Aggs(name+esearch.ReverseNested, reverseNestedAggs, subAggFuncSet...)
+}
+
+// Sampler 子聚合只在每个分片上得分最高的 shardSize 个文档中进行, 聚合名称为 name + esearch.Sampler
+func (b *Builder) Sampler(name string, shardSize int, subAggFuncSet ...SubAggFunc) *Builder {
+	samplerAggs := &aggs.SamplerAggs{
+		Sampler: aggs.Sampler{
+			ShardSize: shardSize,
+		},
+	}
+
+	return b.Aggs(name+esearch.Sampler, samplerAggs, subAggFuncSet...)
+}
+
+// DiversifiedSampler 与 Sampler 相同, 但是样本中 field 的同一个值最多有 maxDocsPerValue 个文档, 聚合名称为 field + esearch.DiversifiedSampler
+func (b *Builder) DiversifiedSampler(field string, maxDocsPerValue int, param aggs.DiversifiedSamplerParam, subAggFuncSet ...SubAggFunc) *Builder {
+	diversifiedSamplerAggs := &aggs.DiversifiedSamplerAggs{
+		DiversifiedSampler: aggs.DiversifiedSampler{
+			Field:                   field,
+			MaxDocsPerValue:         maxDocsPerValue,
+			DiversifiedSamplerParam: param,
+		},
+	}
+
+	name := field
+	if name == "" {
+		name = "script"
+	}
+
+	return b.Aggs(name+esearch.DiversifiedSampler, diversifiedSamplerAggs, subAggFuncSet...)
+}
+
+// RandomSampler 按照 probability 的概率随机抽取文档进行子聚合, probability 的取值范围为 (0, 0.5] 或者 1, seed 相同时抽样结果相同,
+// 聚合名称为 name + esearch.RandomSampler
+func (b *Builder) RandomSampler(name string, probability float64, seed int, subAggFuncSet ...SubAggFunc) *Builder {
+	if probability <= 0 || (probability > 0.5 && probability != 1) {
+		panic("RandomSampler Aggregation setting is fault! probability must be in (0, 0.5] or 1")
+	}
+
+	randomSamplerAggs := &aggs.RandomSamplerAggs{
+		RandomSampler: aggs.RandomSampler{
+			Probability: probability,
+			Seed:        seed,
+		},
+	}
+
+	return b.Aggs(name+esearch.RandomSampler, randomSamplerAggs, subAggFuncSet...)
+}
+
 func (b *Builder) Aggs(aggField string, aggregator esearch.Aggregator, subAggFuncSet ...SubAggFunc) *Builder {
 	agg := &Aggregation{
 		Params:  aggregator,
 		SubAggs: subAggFuncSet,
 	}
+	agg.subBuilder()
 
 	b.aggregations[aggField] = agg
 	return b
@@ -184,6 +493,45 @@ func (b *Builder) Cardinality(field string, param aggs.CardinalityParam) *Builde
 	return b.Aggs(field+esearch.Cardinality, cardinality)
 }
 
+func (b *Builder) Percentiles(field string, percents []float64, param aggs.PercentilesParam) *Builder {
+	percentiles := &aggs.PercentilesAggs{
+		Percentiles: aggs.Percentiles{
+			Field:            field,
+			Percents:         percents,
+			PercentilesParam: param,
+		},
+	}
+
+	return b.Aggs(field+esearch.Percentiles, percentiles)
+}
+
+func (b *Builder) PercentileRanks(field string, values []float64, param aggs.PercentilesParam) *Builder {
+	if len(values) == 0 {
+		return b
+	}
+
+	percentileRanks := &aggs.PercentileRanksAggs{
+		PercentileRanks: aggs.PercentileRanks{
+			Field:            field,
+			Values:           values,
+			PercentilesParam: param,
+		},
+	}
+
+	return b.Aggs(field+esearch.PercentileRanks, percentileRanks)
+}
+
+func (b *Builder) MedianAbsoluteDeviation(field string, param aggs.MedianAbsoluteDeviationParam) *Builder {
+	deviation := &aggs.MedianAbsoluteDeviationAggs{
+		MedianAbsoluteDeviation: aggs.MedianAbsoluteDeviation{
+			Field:                        field,
+			MedianAbsoluteDeviationParam: param,
+		},
+	}
+
+	return b.Aggs(field+esearch.MedianAbsoluteDeviation, deviation)
+}
+
 func (b *Builder) TopHits(hits aggs.TopHitsParam) *Builder {
 	hitsAggs := hits.TopHitsAgg()
 
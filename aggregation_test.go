@@ -0,0 +1,409 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/aggs"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"github.com/KingSolvewer/elasticsearch-query-builder/geo"
+)
+
+func TestMetricAggregation(t *testing.T) {
+	keyed := false
+
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "percentiles hdr array",
+			b: NewBuilder().Size(0).Percentiles("latency", []float64{50, 99}, aggs.PercentilesParam{
+				Keyed: &keyed,
+				Hdr:   &aggs.Hdr{NumberOfSignificantValueDigits: 3},
+			}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"latency_percentiles":{"percentiles":{"field":"latency","percents":[50,99],"keyed":false,"hdr":{"number_of_significant_value_digits":3}}}}}`,
+		},
+		{
+			name: "percentile_ranks tdigest",
+			b:    NewBuilder().Size(0).PercentileRanks("latency", []float64{100}, aggs.PercentilesParam{TDigest: &aggs.TDigest{Compression: 200}}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"latency_percentileRanks":{"percentile_ranks":{"field":"latency","values":[100],"tdigest":{"compression":200}}}}}`,
+		},
+		{
+			name: "percentile_ranks without values is skipped",
+			b:    NewBuilder().Size(0).PercentileRanks("latency", nil, aggs.PercentilesParam{}),
+			want: `{"size":0,"query":{"match_all":{}}}`,
+		},
+		{
+			name: "median_absolute_deviation",
+			b:    NewBuilder().Size(0).MedianAbsoluteDeviation("latency", aggs.MedianAbsoluteDeviationParam{Compression: 100}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"latency_medianAbsoluteDeviation":{"median_absolute_deviation":{"field":"latency","compression":100}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestBucketAggregation(t *testing.T) {
+	superset := false
+	zero := 0
+
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "nested and reverse_nested",
+			b: NewBuilder().Size(0).NestedAggs("comments", func(b *Builder) {
+				b.GroupBy("comments.author", aggs.TermsParam{Size: 5}, func(b *Builder) {
+					b.ReverseNested("", func(b *Builder) {
+						b.GroupBy("tags", aggs.TermsParam{})
+					})
+				})
+			}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"comments_nested":{"nested":{"path":"comments"},"aggs":{"comments.author_terms":{"terms":{"field":"comments.author","size":5},"aggs":{"root_reverseNested":{"reverse_nested":{},"aggs":{"tags_terms":{"terms":{"field":"tags"}}}}}}}}}}`,
+		},
+		{
+			name: "reverse_nested with path",
+			b: NewBuilder().Size(0).NestedAggs("comments.replies", func(b *Builder) {
+				b.ReverseNested("comments")
+			}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"comments.replies_nested":{"nested":{"path":"comments.replies"},"aggs":{"comments_reverseNested":{"reverse_nested":{"path":"comments"}}}}}}`,
+		},
+		{
+			name: "filter keeps minimum_should_match",
+			b: NewBuilder().Size(0).AggsFilter("tag", func(b *Builder) {
+				b.OrWhere("tag", "a").OrWhere("tag", "b").MinimumShouldMatch(1)
+			}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"tag_filter":{"filter":{"bool":{"should":[{"term":{"tag":"a"}},{"term":{"tag":"b"}}],"minimum_should_match":1}}}}}`,
+		},
+		{
+			name: "filter without minimum_should_match is unchanged",
+			b: NewBuilder().Size(0).AggsFilter("tag", func(b *Builder) {
+				b.OrWhere("tag", "a").OrWhere("tag", "b")
+			}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"tag_filter":{"filter":{"bool":{"should":[{"term":{"tag":"a"}},{"term":{"tag":"b"}}]}}}}}`,
+		},
+		{
+			name: "filters with other bucket",
+			b: NewBuilder().Size(0).AggsFilters("sentiment", map[string]NestWhereFunc{
+				"positive": func(b *Builder) { b.Where("sentiment", 1) },
+				"negative": func(b *Builder) { b.Where("sentiment", -1).Where("lang", "zh") },
+			}, aggs.FiltersParam{OtherBucket: true, OtherBucketKey: "neutral"}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"sentiment_filters":{"filters":{"filters":{"negative":{"bool":{"must":[{"term":{"sentiment":-1}},{"term":{"lang":"zh"}}]}},"positive":{"bool":{"must":[{"term":{"sentiment":1}}]}}},"other_bucket":true,"other_bucket_key":"neutral"}}}}`,
+		},
+		{
+			name: "filters without filter funcs is skipped",
+			b:    NewBuilder().Size(0).AggsFilters("sentiment", map[string]NestWhereFunc{"positive": nil}, aggs.FiltersParam{}),
+			want: `{"size":0,"query":{"match_all":{}}}`,
+		},
+		{
+			name: "adjacency_matrix keeps minimum_should_match",
+			b: NewBuilder().Size(0).AdjacencyMatrix("co", map[string]NestWhereFunc{
+				"a": func(b *Builder) { b.Where("tag", "a") },
+				"b": func(b *Builder) { b.OrWhere("tag", "b").OrWhere("tag", "c").MinimumShouldMatch(1) },
+			}, "&"),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"co_adjacencyMatrix":{"adjacency_matrix":{"filters":{"a":{"bool":{"must":[{"term":{"tag":"a"}}]}},"b":{"bool":{"should":[{"term":{"tag":"b"}},{"term":{"tag":"c"}}],"minimum_should_match":1}}},"separator":"&"}}}}`,
+		},
+		{
+			name: "significant_terms with background filter and chi_square",
+			b: NewBuilder().Size(0).Where("sentiment", -1).SignificantTerms("tags", aggs.SignificantTermsParam{
+				SignificantParam: aggs.SignificantParam{Size: 10, ChiSquare: &aggs.SignificanceHeuristic{IncludeNegatives: true, BackgroundIsSuperset: &superset}},
+			}, func(b *Builder) { b.Where("lang", "zh") }),
+			want: `{"size":0,"query":{"bool":{"must":[{"term":{"sentiment":-1}}]}},"aggs":{"tags_significantTerms":{"significant_terms":{"field":"tags","background_filter":{"bool":{"must":[{"term":{"lang":"zh"}}]}},"size":10,"chi_square":{"include_negatives":true,"background_is_superset":false}}}}}`,
+		},
+		{
+			name: "significant_terms min_doc_count 0 and execution hint",
+			b: NewBuilder().Size(0).SignificantTerms("tags", aggs.SignificantTermsParam{
+				ExecutionHint:    aggs.Map,
+				SignificantParam: aggs.SignificantParam{MinDocCount: &zero},
+			}, nil),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"tags_significantTerms":{"significant_terms":{"field":"tags","execution_hint":"map","min_doc_count":0}}}}`,
+		},
+		{
+			name: "significant_text filter_duplicate_text",
+			b:    NewBuilder().Size(0).SignificantText("content", aggs.SignificantTextParam{FilterDuplicateText: true}, nil),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"content_significantText":{"significant_text":{"field":"content","filter_duplicate_text":true}}}}`,
+		},
+		{
+			name: "rare_terms",
+			b:    NewBuilder().Size(0).RareTerms("author", aggs.RareTermsParam{MaxDocCount: 2}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"author_rareTerms":{"rare_terms":{"field":"author","max_doc_count":2}}}}`,
+		},
+		{
+			name: "terms full parameters",
+			b: NewBuilder().Size(0).GroupBy("author", aggs.TermsParam{
+				Size:                  10,
+				Order:                 esearch.SortMap{"_count": esearch.Desc},
+				MinDocCount:           &zero,
+				ShardSize:             50,
+				Include:               aggs.Partition{Partition: 1, NumPartitions: 10},
+				Exclude:               "bot.*",
+				Missing:               "N/A",
+				ExecutionHint:         aggs.Map,
+				CollectMode:           aggs.BreadthFirst,
+				ShowTermDocCountError: true,
+				ValueType:             "string",
+			}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"author_terms":{"terms":{"field":"author","size":10,"order":{"_count":"desc"},"min_doc_count":0,"shard_size":50,"include":{"partition":1,"num_partitions":10},"exclude":"bot.*","missing":"N/A","execution_hint":"map","collect_mode":"breadth_first","show_term_doc_count_error":true,"value_type":"string"}}}}`,
+		},
+		{
+			name: "multi_terms",
+			b:    NewBuilder().Size(0).MultiGroupBy("pair", []aggs.MultiTermsField{{Field: "platform"}, {Field: "year", Missing: 0}}, aggs.MultiTermsParam{Size: 5}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"pair_multiTerms":{"multi_terms":{"terms":[{"field":"platform"},{"field":"year","missing":0}],"size":5}}}}`,
+		},
+		{
+			name: "multi_terms with one field is skipped",
+			b:    NewBuilder().Size(0).MultiGroupBy("pair", []aggs.MultiTermsField{{Field: "platform"}}, aggs.MultiTermsParam{}),
+			want: `{"size":0,"query":{"match_all":{}}}`,
+		},
+		{
+			name: "sampler",
+			b: NewBuilder().Size(0).Sampler("sample", 200, func(b *Builder) {
+				b.GroupBy("tags", aggs.TermsParam{})
+			}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"sample_sampler":{"sampler":{"shard_size":200},"aggs":{"tags_terms":{"terms":{"field":"tags"}}}}}}`,
+		},
+		{
+			name: "diversified_sampler",
+			b: NewBuilder().Size(0).DiversifiedSampler("author", 3, aggs.DiversifiedSamplerParam{ShardSize: 100}, func(b *Builder) {
+				b.GroupBy("tags", aggs.TermsParam{})
+			}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"author_diversifiedSampler":{"diversified_sampler":{"field":"author","max_docs_per_value":3,"shard_size":100},"aggs":{"tags_terms":{"terms":{"field":"tags"}}}}}}`,
+		},
+		{
+			name: "random_sampler",
+			b: NewBuilder().Size(0).RandomSampler("random", 0.1, 42, func(b *Builder) {
+				b.Avg("price", aggs.MetricParam{})
+			}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"random_randomSampler":{"random_sampler":{"probability":0.1,"seed":42},"aggs":{"price_avg":{"avg":{"field":"price"}}}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestRandomSamplerProbabilityPanics(t *testing.T) {
+	tests := []struct {
+		name        string
+		probability float64
+	}{
+		{name: "zero", probability: 0},
+		{name: "negative", probability: -0.1},
+		{name: "between 0.5 and 1", probability: 0.7},
+		{name: "greater than 1", probability: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPanic(t, func() {
+				NewBuilder().RandomSampler("random", tt.probability, 0)
+			})
+		})
+	}
+}
+
+func TestGeoAggregation(t *testing.T) {
+	wrapLongitude := false
+
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "geohash_grid distance precision",
+			b:    NewBuilder().Size(0).GeoHashGrid("location", "10km", aggs.GeoGridParam{Size: 100}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"location_geohashGrid":{"geohash_grid":{"field":"location","precision":"10km","size":100}}}}`,
+		},
+		{
+			name: "geotile_grid with bounds",
+			b: NewBuilder().Size(0).GeoTileGrid("location", 8, aggs.GeoGridParam{
+				Bounds: &geo.BoundingBox{TopLeft: geo.LatLon{Lat: 40, Lon: 110}, BottomRight: geo.LatLon{Lat: 30, Lon: 120}},
+			}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"location_geotileGrid":{"geotile_grid":{"field":"location","precision":8,"bounds":{"top_left":{"lat":40,"lon":110},"bottom_right":{"lat":30,"lon":120}}}}}}`,
+		},
+		{
+			name: "geotile_grid zoom 0 is kept",
+			b:    NewBuilder().Size(0).GeoTileGrid("location", 0, aggs.GeoGridParam{}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"location_geotileGrid":{"geotile_grid":{"field":"location","precision":0}}}}`,
+		},
+		{
+			name: "geo_distance keyed ranges",
+			b: NewBuilder().Size(0).GeoDistanceRange("location", geo.LatLon{Lat: 39.9, Lon: 116.4}, aggs.GeoDistanceParam{
+				Unit:   "km",
+				Keyed:  true,
+				Ranges: []aggs.Ranges{{To: 10}, {From: 10, Key: "far"}},
+			}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"location_geoDistance":{"geo_distance":{"field":"location","origin":{"lat":39.9,"lon":116.4},"unit":"km","keyed":true,"ranges":[{"to":10},{"from":10,"key":"far"}]}}}}`,
+		},
+		{
+			name: "geo_bounds and geo_centroid",
+			b:    NewBuilder().Size(0).GeoBounds("location", &wrapLongitude).GeoCentroid("location"),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"location_geoBounds":{"geo_bounds":{"field":"location","wrap_longitude":false}},"location_geoCentroid":{"geo_centroid":{"field":"location"}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestGeoAggregationPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{
+			name: "geotile_grid precision below 0",
+			fn:   func() { NewBuilder().GeoTileGrid("location", -1, aggs.GeoGridParam{}) },
+		},
+		{
+			name: "geotile_grid precision above 29",
+			fn:   func() { NewBuilder().GeoTileGrid("location", 30, aggs.GeoGridParam{}) },
+		},
+		{
+			name: "geo_distance without origin",
+			fn: func() {
+				NewBuilder().GeoDistanceRange("location", nil, aggs.GeoDistanceParam{Ranges: []aggs.Ranges{{To: 10}}})
+			},
+		},
+		{
+			name: "geo_distance without ranges",
+			fn:   func() { NewBuilder().GeoDistanceRange("location", geo.LatLon{}, aggs.GeoDistanceParam{}) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPanic(t, tt.fn)
+		})
+	}
+}
+
+func TestHistogramAggregation(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "date_histogram calendar interval is lower cased",
+			b: NewBuilder().Size(0).DateGroupBy("date", aggs.HistogramParam{
+				CalendarInterval: "Month",
+				TimeZone:         "+08:00",
+				HardBounds:       map[string]any{"min": "2024-01-01", "max": "2024-12-31"},
+				Keyed:            true,
+				Missing:          "2024-01-01",
+			}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"date_dateHistogram":{"date_histogram":{"field":"date","calendar_interval":"month","time_zone":"+08:00","hard_bounds":{"max":"2024-12-31","min":"2024-01-01"},"keyed":true,"missing":"2024-01-01"}}}}`,
+		},
+		{
+			name: "date_histogram fixed interval micros",
+			b:    NewBuilder().Size(0).DateGroupBy("date", aggs.HistogramParam{FixedInterval: "500micros"}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"date_dateHistogram":{"date_histogram":{"field":"date","fixed_interval":"500micros"}}}}`,
+		},
+		{
+			name: "auto_date_histogram minimum interval is lower cased",
+			b:    NewBuilder().Size(0).AutoDateGroupBy("date", 10, "Day", aggs.AutoDateHistogramParam{}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"date_autoDateHistogram":{"auto_date_histogram":{"field":"date","buckets":10,"minimum_interval":"day"}}}}`,
+		},
+		{
+			name: "variable_width_histogram",
+			b:    NewBuilder().Size(0).VariableWidthHistogram("price", 5, aggs.VariableWidthHistogramParam{}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"price_variableWidthHistogram":{"variable_width_histogram":{"field":"price","buckets":5}}}}`,
+		},
+		{
+			name: "date_histogram without interval",
+			b:    NewBuilder().Size(0).DateGroupBy("date", aggs.HistogramParam{MinDocCount: 1}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"date_dateHistogram":{"date_histogram":{"field":"date","min_doc_count":1}}}}`,
+		},
+		{
+			name: "numeric histogram",
+			b:    NewBuilder().Size(0).Histogram("price", aggs.HistogramParam{Interval: 10, Keyed: true}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"price_histogram":{"histogram":{"field":"price","interval":10,"keyed":true}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestDateGroupByInterval(t *testing.T) {
+	tests := []struct {
+		name      string
+		param     aggs.HistogramParam
+		wantPanic bool
+	}{
+		{name: "calendar word", param: aggs.HistogramParam{CalendarInterval: "quarter"}},
+		{name: "calendar upper case word", param: aggs.HistogramParam{CalendarInterval: "WEEK"}},
+		{name: "calendar minute", param: aggs.HistogramParam{CalendarInterval: "1m"}},
+		{name: "calendar month", param: aggs.HistogramParam{CalendarInterval: "1M"}},
+		{name: "calendar second", param: aggs.HistogramParam{CalendarInterval: "1s"}},
+		{name: "fixed nanos", param: aggs.HistogramParam{FixedInterval: "100nanos"}},
+		{name: "fixed hours", param: aggs.HistogramParam{FixedInterval: "12h"}},
+		{name: "legacy interval", param: aggs.HistogramParam{Interval: "1d"}},
+		{name: "calendar multiple units", param: aggs.HistogramParam{CalendarInterval: "2d"}, wantPanic: true},
+		{name: "calendar upper case unit", param: aggs.HistogramParam{CalendarInterval: "1D"}, wantPanic: true},
+		{name: "fixed month", param: aggs.HistogramParam{FixedInterval: "1M"}, wantPanic: true},
+		{name: "fixed zero", param: aggs.HistogramParam{FixedInterval: "0s"}, wantPanic: true},
+		{name: "no interval", param: aggs.HistogramParam{}},
+		{name: "both calendar and fixed", param: aggs.HistogramParam{CalendarInterval: "day", FixedInterval: "1d"}, wantPanic: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := func() {
+				NewBuilder().DateGroupBy("date", tt.param)
+			}
+
+			if tt.wantPanic {
+				assertPanic(t, fn)
+			} else {
+				fn()
+			}
+		})
+	}
+}
+
+func TestHistogramPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{
+			name: "histogram with calendar interval",
+			fn:   func() { NewBuilder().Histogram("price", aggs.HistogramParam{CalendarInterval: "day"}) },
+		},
+		{
+			name: "histogram with time zone",
+			fn:   func() { NewBuilder().Histogram("price", aggs.HistogramParam{Interval: 10, TimeZone: "+08:00"}) },
+		},
+		{
+			name: "auto_date_histogram with invalid minimum interval",
+			fn: func() {
+				NewBuilder().AutoDateGroupBy("date", 10, "week", aggs.AutoDateHistogramParam{})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPanic(t, tt.fn)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package aggs
 
 import (
+	"encoding/json"
 	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
 )
 
@@ -12,19 +13,168 @@ type TermsAggs struct {
 }
 
 type Terms struct {
-	Field string `json:"field"`
+	Field string `json:"field,omitempty"`
 	TermsParam
 }
 
+// TermsParam Script 不为空时, 使用脚本计算的值进行分组, 可以不设置 Field. MinDocCount 为 0 时返回没有文档的词
 type TermsParam struct {
-	Size  int             `json:"size,omitempty"`
-	Order esearch.SortMap `json:"order,omitempty"`
+	Size                  int             `json:"size,omitempty"`
+	Order                 esearch.SortMap `json:"order,omitempty"`
+	MinDocCount           *int            `json:"min_doc_count,omitempty"`
+	ShardSize             int             `json:"shard_size,omitempty"`
+	ShardMinDocCount      int             `json:"shard_min_doc_count,omitempty"`
+	Include               any             `json:"include,omitempty"` // 正则表达式字符串, 词的数组或者 Partition
+	Exclude               any             `json:"exclude,omitempty"` // 正则表达式字符串或者词的数组
+	Missing               any             `json:"missing,omitempty"`
+	ExecutionHint         ExecutionHint   `json:"execution_hint,omitempty"`
+	CollectMode           CollectMode     `json:"collect_mode,omitempty"`
+	ShowTermDocCountError bool            `json:"show_term_doc_count_error,omitempty"`
+	ValueType             string          `json:"value_type,omitempty"`
+	Format                string          `json:"format,omitempty"`
+	Script                *esearch.Script `json:"script,omitempty"`
+}
+
+type CollectMode string
+
+const (
+	DepthFirst   CollectMode = "depth_first"
+	BreadthFirst CollectMode = "breadth_first"
+)
+
+// Partition include 分区, 把词分成 NumPartitions 份, 只聚合第 Partition 份, 用于分批获取全部的词
+type Partition struct {
+	Partition     int `json:"partition"`
+	NumPartitions int `json:"num_partitions"`
 }
 
 func (agg *TermsAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
 	agg.Aggs = subAgg
 }
 
+type MultiTermsAggs struct {
+	MultiTerms `json:"multi_terms"`
+	Aggs       map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+type MultiTerms struct {
+	Terms []MultiTermsField `json:"terms"`
+	MultiTermsParam
+}
+
+type MultiTermsField struct {
+	Field   string          `json:"field,omitempty"`
+	Missing any             `json:"missing,omitempty"`
+	Script  *esearch.Script `json:"script,omitempty"`
+}
+
+type MultiTermsParam struct {
+	Size                  int             `json:"size,omitempty"`
+	ShardSize             int             `json:"shard_size,omitempty"`
+	MinDocCount           *int            `json:"min_doc_count,omitempty"`
+	ShardMinDocCount      int             `json:"shard_min_doc_count,omitempty"`
+	Order                 esearch.SortMap `json:"order,omitempty"`
+	CollectMode           CollectMode     `json:"collect_mode,omitempty"`
+	ShowTermDocCountError bool            `json:"show_term_doc_count_error,omitempty"`
+}
+
+func (agg *MultiTermsAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+type SignificantTermsAggs struct {
+	SignificantTerms `json:"significant_terms"`
+	Aggs             map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+type SignificantTerms struct {
+	Field            string        `json:"field"`
+	BackgroundFilter esearch.Query `json:"background_filter,omitempty"`
+	SignificantTermsParam
+}
+
+type SignificantTermsParam struct {
+	ExecutionHint ExecutionHint `json:"execution_hint,omitempty"`
+	SignificantParam
+}
+
+type ExecutionHint string
+
+const (
+	Map            ExecutionHint = "map"
+	GlobalOrdinals ExecutionHint = "global_ordinals"
+	BytesHash      ExecutionHint = "bytes_hash" // 只用于 diversified_sampler
+)
+
+func (agg *SignificantTermsAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+type SignificantTextAggs struct {
+	SignificantText `json:"significant_text"`
+	Aggs            map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+type SignificantText struct {
+	Field            string        `json:"field"`
+	BackgroundFilter esearch.Query `json:"background_filter,omitempty"`
+	SignificantTextParam
+}
+
+// SignificantTextParam FilterDuplicateText 为 true 时过滤转载等重复的文本片段, SourceFields 为从 _source 中读取文本的字段
+type SignificantTextParam struct {
+	FilterDuplicateText bool     `json:"filter_duplicate_text,omitempty"`
+	SourceFields        []string `json:"source_fields,omitempty"`
+	SignificantParam
+}
+
+func (agg *SignificantTextAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+// SignificantParam ChiSquare, Gnd, Jlh, MutualInformation 只能设置一个, 都不设置时 es 默认使用 jlh
+type SignificantParam struct {
+	Size              int                    `json:"size,omitempty"`
+	ShardSize         int                    `json:"shard_size,omitempty"`
+	MinDocCount       *int                   `json:"min_doc_count,omitempty"` // es 默认为 3
+	ShardMinDocCount  int                    `json:"shard_min_doc_count,omitempty"`
+	Include           any                    `json:"include,omitempty"`
+	Exclude           any                    `json:"exclude,omitempty"`
+	ChiSquare         *SignificanceHeuristic `json:"chi_square,omitempty"`
+	Gnd               *SignificanceHeuristic `json:"gnd,omitempty"`
+	Jlh               *SignificanceHeuristic `json:"jlh,omitempty"`
+	MutualInformation *SignificanceHeuristic `json:"mutual_information,omitempty"`
+}
+
+// SignificanceHeuristic gnd 只支持 BackgroundIsSuperset, jlh 没有参数
+type SignificanceHeuristic struct {
+	IncludeNegatives     bool  `json:"include_negatives,omitempty"`
+	BackgroundIsSuperset *bool `json:"background_is_superset,omitempty"`
+}
+
+type RareTermsAggs struct {
+	RareTerms `json:"rare_terms"`
+	Aggs      map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+type RareTerms struct {
+	Field string `json:"field"`
+	RareTermsParam
+}
+
+// RareTermsParam MaxDocCount 为文档数量的上限, es 默认为 1, 最大为 100
+type RareTermsParam struct {
+	MaxDocCount int     `json:"max_doc_count,omitempty"`
+	Precision   float64 `json:"precision,omitempty"`
+	Include     any     `json:"include,omitempty"`
+	Exclude     any     `json:"exclude,omitempty"`
+	Missing     any     `json:"missing,omitempty"`
+}
+
+func (agg *RareTermsAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
 type HistogramAggs struct {
 	Histogram `json:"histogram"`
 	Aggs      map[string]esearch.Aggregator `json:"aggs,omitempty"`
@@ -35,13 +185,22 @@ type Histogram struct {
 	HistogramParam
 }
 
+// HistogramParam date_histogram 中 Interval 已经被废弃, 使用 CalendarInterval 或者 FixedInterval, 三者只能设置一个.
+// CalendarInterval 为单个日历单位, 例如 day, 1d, 1M, FixedInterval 为固定时长, 例如 12h, 30m, 500micros.
+// CalendarInterval, FixedInterval, TimeZone 只用于 date_histogram
 type HistogramParam struct {
-	Interval       any                          `json:"interval"`
-	MinDocCount    int                          `json:"min_doc_count,omitempty"`
-	ExtendedBounds map[string]any               `json:"extended_bounds,omitempty"`
-	Order          map[string]esearch.OrderType `json:"order,omitempty"`
-	Offset         any                          `json:"offset,omitempty"`
-	Format         string                       `json:"format,omitempty"`
+	Interval         any                          `json:"interval,omitempty"`
+	CalendarInterval string                       `json:"calendar_interval,omitempty"`
+	FixedInterval    string                       `json:"fixed_interval,omitempty"`
+	TimeZone         string                       `json:"time_zone,omitempty"`
+	MinDocCount      int                          `json:"min_doc_count,omitempty"`
+	ExtendedBounds   map[string]any               `json:"extended_bounds,omitempty"`
+	HardBounds       map[string]any               `json:"hard_bounds,omitempty"`
+	Order            map[string]esearch.OrderType `json:"order,omitempty"`
+	Offset           any                          `json:"offset,omitempty"`
+	Format           string                       `json:"format,omitempty"`
+	Keyed            bool                         `json:"keyed,omitempty"`
+	Missing          any                          `json:"missing,omitempty"`
 }
 
 func (agg *HistogramAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
@@ -57,6 +216,48 @@ func (agg *DateHistogramAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
 	agg.Aggs = subAgg
 }
 
+type AutoDateHistogramAggs struct {
+	AutoDateHistogram `json:"auto_date_histogram"`
+	Aggs              map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+type AutoDateHistogram struct {
+	Field           string `json:"field"`
+	Buckets         int    `json:"buckets,omitempty"`
+	MinimumInterval string `json:"minimum_interval,omitempty"`
+	AutoDateHistogramParam
+}
+
+type AutoDateHistogramParam struct {
+	TimeZone string `json:"time_zone,omitempty"`
+	Format   string `json:"format,omitempty"`
+	Missing  any    `json:"missing,omitempty"`
+}
+
+func (agg *AutoDateHistogramAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+type VariableWidthHistogramAggs struct {
+	VariableWidthHistogram `json:"variable_width_histogram"`
+	Aggs                   map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+type VariableWidthHistogram struct {
+	Field   string `json:"field"`
+	Buckets int    `json:"buckets,omitempty"`
+	VariableWidthHistogramParam
+}
+
+type VariableWidthHistogramParam struct {
+	ShardSize     int `json:"shard_size,omitempty"`
+	InitialBuffer int `json:"initial_buffer,omitempty"`
+}
+
+func (agg *VariableWidthHistogramAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
 type RangeAggs struct {
 	Range `json:"range"`
 	Aggs  map[string]esearch.Aggregator `json:"aggs,omitempty"`
@@ -92,17 +293,96 @@ func (agg *DateRangeAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
 	agg.Aggs = subAgg
 }
 
+type NestedAggs struct {
+	Nested `json:"nested"`
+	Aggs   map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+type Nested struct {
+	Path string `json:"path"`
+}
+
+func (agg *NestedAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+type ReverseNestedAggs struct {
+	ReverseNested `json:"reverse_nested"`
+	Aggs          map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+// ReverseNested Path 为空时回到根文档
+type ReverseNested struct {
+	Path string `json:"path,omitempty"`
+}
+
+func (agg *ReverseNestedAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+type SamplerAggs struct {
+	Sampler `json:"sampler"`
+	Aggs    map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+// Sampler ShardSize 为每个分片上参与子聚合的得分最高的文档数量, es 默认为 100
+type Sampler struct {
+	ShardSize int `json:"shard_size,omitempty"`
+}
+
+func (agg *SamplerAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+type DiversifiedSamplerAggs struct {
+	DiversifiedSampler `json:"diversified_sampler"`
+	Aggs               map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+// DiversifiedSampler 限制样本中 Field 的同一个值最多有 MaxDocsPerValue 个文档, 避免样本集中在少数几个值上
+type DiversifiedSampler struct {
+	Field           string `json:"field,omitempty"`
+	MaxDocsPerValue int    `json:"max_docs_per_value,omitempty"`
+	DiversifiedSamplerParam
+}
+
+type DiversifiedSamplerParam struct {
+	ShardSize     int             `json:"shard_size,omitempty"`
+	ExecutionHint ExecutionHint   `json:"execution_hint,omitempty"`
+	Script        *esearch.Script `json:"script,omitempty"`
+}
+
+func (agg *DiversifiedSamplerAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+type RandomSamplerAggs struct {
+	RandomSampler `json:"random_sampler"`
+	Aggs          map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+type RandomSampler struct {
+	Probability float64 `json:"probability"`
+	Seed        int     `json:"seed,omitempty"`
+}
+
+func (agg *RandomSamplerAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
 type AvgAggs struct {
 	Metric `json:"avg"`
 }
 
 type Metric struct {
-	Field string `json:"field"`
+	Field string `json:"field,omitempty"`
 	MetricParam
 }
 
+// MetricParam Script 不为空时, 使用脚本计算的值进行聚合, 可以不设置 Field
 type MetricParam struct {
-	Missing int `json:"missing,omitempty"`
+	Missing int             `json:"missing,omitempty"`
+	Script  *esearch.Script `json:"script,omitempty"`
 }
 
 func (metric *AvgAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
@@ -167,6 +447,68 @@ type CardinalityParam struct {
 func (metric *CardinalityAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
 }
 
+type PercentilesAggs struct {
+	Percentiles `json:"percentiles"`
+}
+
+type Percentiles struct {
+	Field    string    `json:"field,omitempty"`
+	Percents []float64 `json:"percents,omitempty"`
+	PercentilesParam
+}
+
+// PercentilesParam TDigest 和 Hdr 二选一, 不设置时 es 默认使用 tdigest
+type PercentilesParam struct {
+	Keyed   *bool           `json:"keyed,omitempty"` // es 默认为 true
+	TDigest *TDigest        `json:"tdigest,omitempty"`
+	Hdr     *Hdr            `json:"hdr,omitempty"`
+	Missing any             `json:"missing,omitempty"`
+	Script  *esearch.Script `json:"script,omitempty"`
+}
+
+type TDigest struct {
+	Compression   float64 `json:"compression,omitempty"`
+	ExecutionHint string  `json:"execution_hint,omitempty"`
+}
+
+type Hdr struct {
+	NumberOfSignificantValueDigits int `json:"number_of_significant_value_digits"`
+}
+
+func (metric *PercentilesAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+type PercentileRanksAggs struct {
+	PercentileRanks `json:"percentile_ranks"`
+}
+
+type PercentileRanks struct {
+	Field  string    `json:"field,omitempty"`
+	Values []float64 `json:"values"`
+	PercentilesParam
+}
+
+func (metric *PercentileRanksAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+type MedianAbsoluteDeviationAggs struct {
+	MedianAbsoluteDeviation `json:"median_absolute_deviation"`
+}
+
+type MedianAbsoluteDeviation struct {
+	Field string `json:"field,omitempty"`
+	MedianAbsoluteDeviationParam
+}
+
+type MedianAbsoluteDeviationParam struct {
+	Compression float64         `json:"compression,omitempty"`
+	Missing     any             `json:"missing,omitempty"`
+	Script      *esearch.Script `json:"script,omitempty"`
+}
+
+func (metric *MedianAbsoluteDeviationAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
 type TopHitsAggs struct {
 	TopHits `json:"top_hits"`
 }
@@ -235,3 +577,143 @@ type FilterAggs struct {
 func (agg *FilterAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
 	agg.Aggs = subAgg
 }
+
+type FiltersAggs struct {
+	Filters `json:"filters"`
+	Aggs    map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+type Filters struct {
+	Filters map[string]esearch.Query `json:"filters"`
+	FiltersParam
+}
+
+// FiltersParam OtherBucket 为 true 时, 不满足任何过滤条件的文档放入 OtherBucketKey 桶中, OtherBucketKey 默认为 _other_
+type FiltersParam struct {
+	OtherBucket    bool   `json:"other_bucket,omitempty"`
+	OtherBucketKey string `json:"other_bucket_key,omitempty"`
+}
+
+func (agg *FiltersAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+type AdjacencyMatrixAggs struct {
+	AdjacencyMatrix `json:"adjacency_matrix"`
+	Aggs            map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+// AdjacencyMatrix Separator 为交集桶名称的连接符, 默认为 &
+type AdjacencyMatrix struct {
+	Filters   map[string]esearch.Query `json:"filters"`
+	Separator string                   `json:"separator,omitempty"`
+}
+
+func (agg *AdjacencyMatrixAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+type CompositeAggs struct {
+	Composite `json:"composite"`
+	Aggs      map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+type Composite struct {
+	Size    int               `json:"size,omitempty"`
+	Sources []CompositeSource `json:"sources"`
+	After   map[string]any    `json:"after,omitempty"`
+}
+
+func (agg *CompositeAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+// CompositeSource composite 聚合的数据源, SourceName 作为 bucket key 和 after_key 中的键
+type CompositeSource interface {
+	SourceName() string
+}
+
+// CompositeSourceParam 各数据源通用的参数
+type CompositeSourceParam struct {
+	Order         esearch.OrderType `json:"order,omitempty"`
+	MissingBucket bool              `json:"missing_bucket,omitempty"`
+	MissingOrder  string            `json:"missing_order,omitempty"` // first, last, default
+	Script        *esearch.Script   `json:"script,omitempty"`
+}
+
+type TermsSource struct {
+	Name  string `json:"-"`
+	Field string `json:"field,omitempty"`
+	CompositeSourceParam
+}
+
+func (s TermsSource) SourceName() string {
+	return s.Name
+}
+
+func (s TermsSource) MarshalJSON() ([]byte, error) {
+	type termsSource TermsSource
+	return sourceMarshal(s.Name, "terms", termsSource(s))
+}
+
+type HistogramSource struct {
+	Name     string `json:"-"`
+	Field    string `json:"field,omitempty"`
+	Interval any    `json:"interval"`
+	CompositeSourceParam
+}
+
+func (s HistogramSource) SourceName() string {
+	return s.Name
+}
+
+func (s HistogramSource) MarshalJSON() ([]byte, error) {
+	type histogramSource HistogramSource
+	return sourceMarshal(s.Name, "histogram", histogramSource(s))
+}
+
+// DateHistogramSource CalendarInterval 和 FixedInterval 二选一
+type DateHistogramSource struct {
+	Name             string `json:"-"`
+	Field            string `json:"field,omitempty"`
+	CalendarInterval string `json:"calendar_interval,omitempty"`
+	FixedInterval    string `json:"fixed_interval,omitempty"`
+	TimeZone         string `json:"time_zone,omitempty"`
+	Offset           string `json:"offset,omitempty"`
+	Format           string `json:"format,omitempty"`
+	CompositeSourceParam
+}
+
+func (s DateHistogramSource) SourceName() string {
+	return s.Name
+}
+
+func (s DateHistogramSource) MarshalJSON() ([]byte, error) {
+	type dateHistogramSource DateHistogramSource
+	return sourceMarshal(s.Name, "date_histogram", dateHistogramSource(s))
+}
+
+type GeoTileGridSource struct {
+	Name      string `json:"-"`
+	Field     string `json:"field"`
+	Precision int    `json:"precision,omitempty"`
+	Bounds    any    `json:"bounds,omitempty"`
+	CompositeSourceParam
+}
+
+func (s GeoTileGridSource) SourceName() string {
+	return s.Name
+}
+
+func (s GeoTileGridSource) MarshalJSON() ([]byte, error) {
+	type geoTileGridSource GeoTileGridSource
+	return sourceMarshal(s.Name, "geotile_grid", geoTileGridSource(s))
+}
+
+func sourceMarshal(name string, typ string, source any) ([]byte, error) {
+	return json.Marshal(map[string]map[string]any{
+		name: {
+			typ: source,
+		},
+	})
+}
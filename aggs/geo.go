@@ -0,0 +1,84 @@
+package aggs
+
+import (
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"github.com/KingSolvewer/elasticsearch-query-builder/geo"
+)
+
+type GeoHashGridAggs struct {
+	GeoGrid `json:"geohash_grid"`
+	Aggs    map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+func (agg *GeoHashGridAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+type GeoTileGridAggs struct {
+	GeoGrid `json:"geotile_grid"`
+	Aggs    map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+func (agg *GeoTileGridAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+// GeoGrid geohash_grid 的 Precision 为 1 到 12 或者距离, 例如 1km, geotile_grid 的 Precision 为 0 到 29
+type GeoGrid struct {
+	Field     string `json:"field"`
+	Precision any    `json:"precision,omitempty"`
+	GeoGridParam
+}
+
+// GeoGridParam Bounds 设置后只聚合边界框内的点
+type GeoGridParam struct {
+	Bounds    *geo.BoundingBox `json:"bounds,omitempty"`
+	Size      int              `json:"size,omitempty"`
+	ShardSize int              `json:"shard_size,omitempty"`
+}
+
+type GeoDistanceAggs struct {
+	GeoDistance `json:"geo_distance"`
+	Aggs        map[string]esearch.Aggregator `json:"aggs,omitempty"`
+}
+
+type GeoDistance struct {
+	Field  string    `json:"field"`
+	Origin geo.Point `json:"origin"`
+	GeoDistanceParam
+}
+
+// GeoDistanceParam Unit 为 Ranges 中距离的单位, es 默认为 m
+type GeoDistanceParam struct {
+	Unit         string           `json:"unit,omitempty"`
+	DistanceType geo.DistanceType `json:"distance_type,omitempty"`
+	Keyed        bool             `json:"keyed,omitempty"`
+	Ranges       []Ranges         `json:"ranges"`
+}
+
+func (agg *GeoDistanceAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+	agg.Aggs = subAgg
+}
+
+type GeoBoundsAggs struct {
+	GeoBounds `json:"geo_bounds"`
+}
+
+type GeoBounds struct {
+	Field         string `json:"field"`
+	WrapLongitude *bool  `json:"wrap_longitude,omitempty"`
+}
+
+func (metric *GeoBoundsAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+type GeoCentroidAggs struct {
+	GeoCentroid `json:"geo_centroid"`
+}
+
+type GeoCentroid struct {
+	Field string `json:"field"`
+}
+
+func (metric *GeoCentroidAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
@@ -0,0 +1,245 @@
+package aggs
+
+import (
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+// BucketsPath 管道聚合引用的聚合路径, 格式为 聚合名称>聚合名称.指标, 例如 sales_dateHistogram>price_sum, price_stats.avg, _count
+type BucketsPath string
+
+// Pipeline 管道聚合, BucketsPaths 返回引用的全部聚合路径, 生成语句时校验路径是否存在
+type Pipeline interface {
+	BucketsPaths() []BucketsPath
+}
+
+type GapPolicy string
+
+const (
+	Skip        GapPolicy = "skip"
+	InsertZeros GapPolicy = "insert_zeros"
+	KeepValues  GapPolicy = "keep_values"
+)
+
+type PipelineParam struct {
+	GapPolicy GapPolicy `json:"gap_policy,omitempty"`
+	Format    string    `json:"format,omitempty"`
+}
+
+type DerivativeAggs struct {
+	Derivative `json:"derivative"`
+}
+
+type Derivative struct {
+	BucketsPath BucketsPath `json:"buckets_path"`
+	DerivativeParam
+}
+
+// DerivativeParam Unit 设置后, 结果中会返回按照该时间单位换算的 normalized_value, 例如 1d
+type DerivativeParam struct {
+	Unit string `json:"unit,omitempty"`
+	PipelineParam
+}
+
+func (agg *DerivativeAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+func (agg *DerivativeAggs) BucketsPaths() []BucketsPath {
+	return []BucketsPath{agg.BucketsPath}
+}
+
+type CumulativeSumAggs struct {
+	CumulativeSum `json:"cumulative_sum"`
+}
+
+type CumulativeSum struct {
+	BucketsPath BucketsPath `json:"buckets_path"`
+	Format      string      `json:"format,omitempty"`
+}
+
+func (agg *CumulativeSumAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+func (agg *CumulativeSumAggs) BucketsPaths() []BucketsPath {
+	return []BucketsPath{agg.BucketsPath}
+}
+
+type MovingFnAggs struct {
+	MovingFn `json:"moving_fn"`
+}
+
+// MovingFn Script 中可以使用 MovingFunctions.unweightedAvg(values) 等内置函数
+type MovingFn struct {
+	BucketsPath BucketsPath `json:"buckets_path"`
+	Window      int         `json:"window"`
+	Script      string      `json:"script"`
+	MovingFnParam
+}
+
+type MovingFnParam struct {
+	Shift     int       `json:"shift,omitempty"`
+	GapPolicy GapPolicy `json:"gap_policy,omitempty"`
+}
+
+func (agg *MovingFnAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+func (agg *MovingFnAggs) BucketsPaths() []BucketsPath {
+	return []BucketsPath{agg.BucketsPath}
+}
+
+type SerialDiffAggs struct {
+	SerialDiff `json:"serial_diff"`
+}
+
+type SerialDiff struct {
+	BucketsPath BucketsPath `json:"buckets_path"`
+	Lag         int         `json:"lag,omitempty"`
+	PipelineParam
+}
+
+func (agg *SerialDiffAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+func (agg *SerialDiffAggs) BucketsPaths() []BucketsPath {
+	return []BucketsPath{agg.BucketsPath}
+}
+
+// BucketMetric avg_bucket, sum_bucket, max_bucket, min_bucket, stats_bucket 的参数
+type BucketMetric struct {
+	BucketsPath BucketsPath `json:"buckets_path"`
+	PipelineParam
+}
+
+func (metric BucketMetric) BucketsPaths() []BucketsPath {
+	return []BucketsPath{metric.BucketsPath}
+}
+
+type AvgBucketAggs struct {
+	BucketMetric `json:"avg_bucket"`
+}
+
+func (agg *AvgBucketAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+type SumBucketAggs struct {
+	BucketMetric `json:"sum_bucket"`
+}
+
+func (agg *SumBucketAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+type MaxBucketAggs struct {
+	BucketMetric `json:"max_bucket"`
+}
+
+func (agg *MaxBucketAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+type MinBucketAggs struct {
+	BucketMetric `json:"min_bucket"`
+}
+
+func (agg *MinBucketAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+type StatsBucketAggs struct {
+	BucketMetric `json:"stats_bucket"`
+}
+
+func (agg *StatsBucketAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+type PercentilesBucketAggs struct {
+	PercentilesBucket `json:"percentiles_bucket"`
+}
+
+type PercentilesBucket struct {
+	BucketsPath BucketsPath `json:"buckets_path"`
+	Percents    []float64   `json:"percents,omitempty"`
+	PipelineParam
+}
+
+func (agg *PercentilesBucketAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+func (agg *PercentilesBucketAggs) BucketsPaths() []BucketsPath {
+	return []BucketsPath{agg.BucketsPath}
+}
+
+type BucketScriptAggs struct {
+	BucketScript `json:"bucket_script"`
+}
+
+// BucketScript BucketsPath 的键为脚本中使用的变量名, 在脚本中通过 params.变量名 引用
+type BucketScript struct {
+	BucketsPath map[string]BucketsPath `json:"buckets_path"`
+	Script      esearch.Script         `json:"script"`
+	PipelineParam
+}
+
+func (agg *BucketScriptAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+func (agg *BucketScriptAggs) BucketsPaths() []BucketsPath {
+	paths := make([]BucketsPath, 0, len(agg.BucketsPath))
+	for _, path := range agg.BucketsPath {
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+type BucketSelectorAggs struct {
+	BucketSelector `json:"bucket_selector"`
+}
+
+// BucketSelector 脚本返回 false 的桶会被过滤掉
+type BucketSelector struct {
+	BucketsPath map[string]BucketsPath `json:"buckets_path"`
+	Script      esearch.Script         `json:"script"`
+	GapPolicy   GapPolicy              `json:"gap_policy,omitempty"`
+}
+
+func (agg *BucketSelectorAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+func (agg *BucketSelectorAggs) BucketsPaths() []BucketsPath {
+	paths := make([]BucketsPath, 0, len(agg.BucketsPath))
+	for _, path := range agg.BucketsPath {
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+type BucketSortAggs struct {
+	BucketSort `json:"bucket_sort"`
+}
+
+// BucketSort Sort 的键为 BucketsPath
+type BucketSort struct {
+	Sort []esearch.Sorter `json:"sort,omitempty"`
+	BucketSortParam
+}
+
+type BucketSortParam struct {
+	From      int       `json:"from,omitempty"`
+	Size      int       `json:"size,omitempty"`
+	GapPolicy GapPolicy `json:"gap_policy,omitempty"`
+}
+
+func (agg *BucketSortAggs) Aggregate(subAgg map[string]esearch.Aggregator) {
+}
+
+func (agg *BucketSortAggs) BucketsPaths() []BucketsPath {
+	paths := make([]BucketsPath, 0, len(agg.Sort))
+	for _, sorter := range agg.Sort {
+		if sort, ok := sorter.(esearch.Sort); ok {
+			for path := range sort {
+				paths = append(paths, BucketsPath(path))
+			}
+		}
+	}
+
+	return paths
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/KingSolvewer/elasticsearch-query-builder/collapse"
 	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
 	"github.com/KingSolvewer/elasticsearch-query-builder/fulltext"
+	"github.com/KingSolvewer/elasticsearch-query-builder/specialized"
 	"github.com/KingSolvewer/elasticsearch-query-builder/termlevel"
 )
 
@@ -24,6 +25,13 @@ type Builder struct {
 	scroll             string
 	scrollId           string
 	collapse           *collapse.Collapser
+	compoundQuery      esearch.Query
+	functionScore      *functionScore
+	scriptScore        *scriptScore
+	pinned             *specialized.Pinned
+	knn                []esearch.Knn
+	retriever          esearch.Retriever
+	rrf                *rrf
 	raw                string
 }
 
@@ -39,6 +47,7 @@ func NewBuilder() *Builder {
 	}
 }
 
+// Dsl 与 Marshal 相同但是忽略错误, 出错时(例如 buckets_path 引用的聚合不存在, 排序与检索器同时使用)返回空字符串, 需要错误信息时使用 Marshal
 func (b *Builder) Dsl() string {
 	dsl, _ := b.Marshal()
 
@@ -49,6 +58,14 @@ func (b *Builder) Marshal() (string, error) {
 	if b.raw != "" {
 		return b.raw, nil
 	} else {
+		if err := b.checkPipelines(); err != nil {
+			return "", err
+		}
+
+		if err := b.checkRetriever(); err != nil {
+			return "", err
+		}
+
 		query := b.compile()
 		bytes, err := json.Marshal(query)
 		return string(bytes), err
@@ -72,6 +89,13 @@ func (b *Builder) Reset() *Builder {
 	b.scroll = ""
 	b.scrollId = ""
 	b.collapse = nil
+	b.compoundQuery = nil
+	b.functionScore = nil
+	b.scriptScore = nil
+	b.pinned = nil
+	b.knn = nil
+	b.retriever = nil
+	b.rrf = nil
 
 	return b
 }
@@ -90,8 +114,9 @@ func (b *Builder) Clone() *Builder {
 	aggregations := make(map[string]*Aggregation)
 	for key, agg := range b.aggregations {
 		aggregations[key] = &Aggregation{
-			Params:  agg.Params,
-			SubAggs: agg.SubAggs,
+			Params:        agg.Params,
+			SubAggs:       agg.SubAggs,
+			subAggBuilder: agg.subAggBuilder,
 		}
 	}
 
@@ -102,6 +127,13 @@ func (b *Builder) Clone() *Builder {
 		postWhere:          b.postWhere,
 		minimumShouldMatch: b.minimumShouldMatch,
 		aggregations:       aggregations,
+		compoundQuery:      b.compoundQuery,
+		functionScore:      b.functionScore,
+		scriptScore:        b.scriptScore,
+		pinned:             b.pinned,
+		knn:                append([]esearch.Knn(nil), b.knn...),
+		retriever:          b.retriever,
+		rrf:                b.rrf,
 	}
 }
 
@@ -230,6 +262,34 @@ func (b *Builder) OrderBy(field string, orderType esearch.OrderType) *Builder {
 	return b
 }
 
+// OrderByScript 使用脚本计算的值排序
+func OrderByScript(script esearch.Script, typ esearch.ScriptSortType, order esearch.OrderType) *Builder {
+	return builder.OrderByScript(script, typ, order)
+}
+
+// OrderByScript 使用脚本计算的值排序
+func (b *Builder) OrderByScript(script esearch.Script, typ esearch.ScriptSortType, orderType esearch.OrderType) *Builder {
+	if typ == "" {
+		typ = esearch.ScriptSortNumber
+	}
+
+	switch orderType {
+	case esearch.Asc, esearch.Desc:
+	default:
+		orderType = esearch.Asc
+	}
+
+	b.sort = append(b.sort, esearch.ScriptSort{
+		Script: esearch.ScriptSortParam{
+			Type:   typ,
+			Script: script,
+			Order:  orderType,
+		},
+	})
+
+	return b
+}
+
 func Order(sort esearch.Sort) *Builder {
 	return builder.Order(sort)
 }
@@ -321,6 +381,66 @@ func (b *Builder) WhereWildcard(field string, value string, fn termlevel.Wildcar
 	return b
 }
 
+// WherePrefixParams Must prefix 查询语句, 支持 case_insensitive, rewrite 参数
+func WherePrefixParams(field string, value string, fn termlevel.PrefixParamFunc) *Builder {
+	return builder.WherePrefixParams(field, value, fn)
+}
+
+// WherePrefixParams Must prefix 查询语句, 支持 case_insensitive, rewrite 参数
+func (b *Builder) WherePrefixParams(field string, value string, fn termlevel.PrefixParamFunc) *Builder {
+	b.prefixParamsQuery(esearch.Must, field, value, fn)
+
+	return b
+}
+
+// WhereFuzzy Must fuzzy 查询语句
+func WhereFuzzy(field string, value string, fn termlevel.FuzzyParamFunc) *Builder {
+	return builder.WhereFuzzy(field, value, fn)
+}
+
+// WhereFuzzy Must fuzzy 查询语句
+func (b *Builder) WhereFuzzy(field string, value string, fn termlevel.FuzzyParamFunc) *Builder {
+	b.fuzzy(esearch.Must, field, value, fn)
+
+	return b
+}
+
+// WhereIds Must ids 查询语句
+func WhereIds(values []string) *Builder {
+	return builder.WhereIds(values)
+}
+
+// WhereIds Must ids 查询语句
+func (b *Builder) WhereIds(values []string) *Builder {
+	b.ids(esearch.Must, values)
+
+	return b
+}
+
+// WhereTermsSet Must terms_set 查询语句
+func WhereTermsSet(field string, value []any, fn termlevel.TermsSetParamFunc) *Builder {
+	return builder.WhereTermsSet(field, value, fn)
+}
+
+// WhereTermsSet Must terms_set 查询语句
+func (b *Builder) WhereTermsSet(field string, value []any, fn termlevel.TermsSetParamFunc) *Builder {
+	b.termsSet(esearch.Must, field, value, fn)
+
+	return b
+}
+
+// WhereMatchNone Must match_none 查询语句
+func WhereMatchNone() *Builder {
+	return builder.WhereMatchNone()
+}
+
+// WhereMatchNone Must match_none 查询语句
+func (b *Builder) WhereMatchNone() *Builder {
+	b.append(esearch.Must, termlevel.TermQuery{MatchNone: &termlevel.MatchNone{}})
+
+	return b
+}
+
 // WhereIn Must terms 查询语句
 func WhereIn(field string, value []any) *Builder {
 	return builder.WhereIn(field, value)
@@ -333,6 +453,23 @@ func (b *Builder) WhereIn(field string, value []any) *Builder {
 	return b
 }
 
+// WhereInLookup Must terms lookup 查询语句, 查询的值为 index 索引中 id 文档的 path 字段
+func WhereInLookup(field string, index string, id string, path string, routing string) *Builder {
+	return builder.WhereInLookup(field, index, id, path, routing)
+}
+
+// WhereInLookup Must terms lookup 查询语句, 查询的值为 index 索引中 id 文档的 path 字段
+func (b *Builder) WhereInLookup(field string, index string, id string, path string, routing string) *Builder {
+	b.termsLookupQuery(esearch.Must, field, termlevel.TermsLookup{
+		Index:   index,
+		Id:      id,
+		Path:    path,
+		Routing: routing,
+	})
+
+	return b
+}
+
 // WhereBetween Must Range ( gte:>=a and lte:<=b) 查询语句
 func WhereBetween(field string, value1, value2 int) *Builder {
 	return builder.WhereBetween(field, value1, value2)
@@ -368,6 +505,17 @@ func (b *Builder) WhereMatch(field string, value string, matchType esearch.Match
 	return b
 }
 
+// WhereMatchPhrase Must match_phrase, match_phrase_prefix 匹配, 可以设置 slop
+func WhereMatchPhrase(field string, value string, matchType esearch.MatchType, fn fulltext.PhraseParamsFunc) *Builder {
+	return builder.WhereMatchPhrase(field, value, matchType, fn)
+}
+
+// WhereMatchPhrase Must match_phrase, match_phrase_prefix 匹配, 可以设置 slop
+func (b *Builder) WhereMatchPhrase(field string, value string, matchType esearch.MatchType, fn fulltext.PhraseParamsFunc) *Builder {
+	b.whereMatchPhrase(esearch.Must, field, value, matchType, fn)
+	return b
+}
+
 // WhereMultiMatch Must multi_match 匹配
 func WhereMultiMatch(field []string, value string, fieldType esearch.FieldType, fn fulltext.AppendParamsFunc) *Builder {
 	return builder.WhereMultiMatch(field, value, fieldType, fn)
@@ -380,6 +528,42 @@ func (b *Builder) WhereMultiMatch(field []string, value string, fieldType esearc
 	return b
 }
 
+// WhereQueryString Must query_string 查询, 支持 lucene 语法
+func WhereQueryString(query string, fn fulltext.QueryStringParamFunc) *Builder {
+	return builder.WhereQueryString(query, fn)
+}
+
+// WhereQueryString Must query_string 查询, 支持 lucene 语法
+func (b *Builder) WhereQueryString(query string, fn fulltext.QueryStringParamFunc) *Builder {
+	b.queryString(esearch.Must, query, fn)
+
+	return b
+}
+
+// WhereSimpleQueryString Must simple_query_string 查询
+func WhereSimpleQueryString(query string, fn fulltext.SimpleQueryStringParamFunc) *Builder {
+	return builder.WhereSimpleQueryString(query, fn)
+}
+
+// WhereSimpleQueryString Must simple_query_string 查询
+func (b *Builder) WhereSimpleQueryString(query string, fn fulltext.SimpleQueryStringParamFunc) *Builder {
+	b.simpleQueryString(esearch.Must, query, fn)
+
+	return b
+}
+
+// WhereMoreLikeThis Must more_like_this 查询, 查询与给定文本或文档相似的文档
+func WhereMoreLikeThis(fields []string, likeSpec fulltext.LikeSpec, fn fulltext.MoreLikeThisParamFunc) *Builder {
+	return builder.WhereMoreLikeThis(fields, likeSpec, fn)
+}
+
+// WhereMoreLikeThis Must more_like_this 查询, 查询与给定文本或文档相似的文档
+func (b *Builder) WhereMoreLikeThis(fields []string, likeSpec fulltext.LikeSpec, fn fulltext.MoreLikeThisParamFunc) *Builder {
+	b.moreLikeThis(esearch.Must, fields, likeSpec, fn)
+
+	return b
+}
+
 // WhereNested Must 嵌套查询, 例如嵌套 should 语句
 func WhereNested(fn NestWhereFunc) *Builder {
 	return builder.WhereNested(fn)
@@ -452,6 +636,66 @@ func (b *Builder) WhereNotWildcard(field string, value string, fn termlevel.Wild
 	return b
 }
 
+// WhereNotPrefixParams MustNot prefix 查询语句, 支持 case_insensitive, rewrite 参数
+func WhereNotPrefixParams(field string, value string, fn termlevel.PrefixParamFunc) *Builder {
+	return builder.WhereNotPrefixParams(field, value, fn)
+}
+
+// WhereNotPrefixParams MustNot prefix 查询语句, 支持 case_insensitive, rewrite 参数
+func (b *Builder) WhereNotPrefixParams(field string, value string, fn termlevel.PrefixParamFunc) *Builder {
+	b.prefixParamsQuery(esearch.MustNot, field, value, fn)
+
+	return b
+}
+
+// WhereNotFuzzy MustNot fuzzy 查询语句
+func WhereNotFuzzy(field string, value string, fn termlevel.FuzzyParamFunc) *Builder {
+	return builder.WhereNotFuzzy(field, value, fn)
+}
+
+// WhereNotFuzzy MustNot fuzzy 查询语句
+func (b *Builder) WhereNotFuzzy(field string, value string, fn termlevel.FuzzyParamFunc) *Builder {
+	b.fuzzy(esearch.MustNot, field, value, fn)
+
+	return b
+}
+
+// WhereNotIds MustNot ids 查询语句
+func WhereNotIds(values []string) *Builder {
+	return builder.WhereNotIds(values)
+}
+
+// WhereNotIds MustNot ids 查询语句
+func (b *Builder) WhereNotIds(values []string) *Builder {
+	b.ids(esearch.MustNot, values)
+
+	return b
+}
+
+// WhereNotTermsSet MustNot terms_set 查询语句
+func WhereNotTermsSet(field string, value []any, fn termlevel.TermsSetParamFunc) *Builder {
+	return builder.WhereNotTermsSet(field, value, fn)
+}
+
+// WhereNotTermsSet MustNot terms_set 查询语句
+func (b *Builder) WhereNotTermsSet(field string, value []any, fn termlevel.TermsSetParamFunc) *Builder {
+	b.termsSet(esearch.MustNot, field, value, fn)
+
+	return b
+}
+
+// WhereNotMatchNone MustNot match_none 查询语句
+func WhereNotMatchNone() *Builder {
+	return builder.WhereNotMatchNone()
+}
+
+// WhereNotMatchNone MustNot match_none 查询语句
+func (b *Builder) WhereNotMatchNone() *Builder {
+	b.append(esearch.MustNot, termlevel.TermQuery{MatchNone: &termlevel.MatchNone{}})
+
+	return b
+}
+
 // WhereNotIn  MustNot terms 查询语句
 func WhereNotIn(field string, value []any) *Builder {
 	return builder.WhereIn(field, value)
@@ -464,6 +708,23 @@ func (b *Builder) WhereNotIn(field string, value []any) *Builder {
 	return b
 }
 
+// WhereNotInLookup MustNot terms lookup 查询语句, 查询的值为 index 索引中 id 文档的 path 字段
+func WhereNotInLookup(field string, index string, id string, path string, routing string) *Builder {
+	return builder.WhereNotInLookup(field, index, id, path, routing)
+}
+
+// WhereNotInLookup MustNot terms lookup 查询语句, 查询的值为 index 索引中 id 文档的 path 字段
+func (b *Builder) WhereNotInLookup(field string, index string, id string, path string, routing string) *Builder {
+	b.termsLookupQuery(esearch.MustNot, field, termlevel.TermsLookup{
+		Index:   index,
+		Id:      id,
+		Path:    path,
+		Routing: routing,
+	})
+
+	return b
+}
+
 // WhereNotBetween MustNot Range ( gte:>=a and lte:<=b) 查询语句
 func WhereNotBetween(field string, value1, value2 int) *Builder {
 	return builder.WhereNotBetween(field, value1, value2)
@@ -499,6 +760,17 @@ func (b *Builder) WhereNotMatch(field string, value string, matchType esearch.Ma
 	return b
 }
 
+// WhereNotMatchPhrase MustNot match_phrase, match_phrase_prefix 匹配, 可以设置 slop
+func WhereNotMatchPhrase(field string, value string, matchType esearch.MatchType, fn fulltext.PhraseParamsFunc) *Builder {
+	return builder.WhereNotMatchPhrase(field, value, matchType, fn)
+}
+
+// WhereNotMatchPhrase MustNot match_phrase, match_phrase_prefix 匹配, 可以设置 slop
+func (b *Builder) WhereNotMatchPhrase(field string, value string, matchType esearch.MatchType, fn fulltext.PhraseParamsFunc) *Builder {
+	b.whereMatchPhrase(esearch.MustNot, field, value, matchType, fn)
+	return b
+}
+
 // WhereNotMultiMatch MustNot multi_match 匹配
 func WhereNotMultiMatch(field []string, value string, fieldType esearch.FieldType, fn fulltext.AppendParamsFunc) *Builder {
 	return builder.WhereNotMultiMatch(field, value, fieldType, fn)
@@ -511,6 +783,42 @@ func (b *Builder) WhereNotMultiMatch(field []string, value string, fieldType ese
 	return b
 }
 
+// WhereNotQueryString MustNot query_string 查询, 支持 lucene 语法
+func WhereNotQueryString(query string, fn fulltext.QueryStringParamFunc) *Builder {
+	return builder.WhereNotQueryString(query, fn)
+}
+
+// WhereNotQueryString MustNot query_string 查询, 支持 lucene 语法
+func (b *Builder) WhereNotQueryString(query string, fn fulltext.QueryStringParamFunc) *Builder {
+	b.queryString(esearch.MustNot, query, fn)
+
+	return b
+}
+
+// WhereNotSimpleQueryString MustNot simple_query_string 查询
+func WhereNotSimpleQueryString(query string, fn fulltext.SimpleQueryStringParamFunc) *Builder {
+	return builder.WhereNotSimpleQueryString(query, fn)
+}
+
+// WhereNotSimpleQueryString MustNot simple_query_string 查询
+func (b *Builder) WhereNotSimpleQueryString(query string, fn fulltext.SimpleQueryStringParamFunc) *Builder {
+	b.simpleQueryString(esearch.MustNot, query, fn)
+
+	return b
+}
+
+// WhereNotMoreLikeThis MustNot more_like_this 查询, 查询与给定文本或文档相似的文档
+func WhereNotMoreLikeThis(fields []string, likeSpec fulltext.LikeSpec, fn fulltext.MoreLikeThisParamFunc) *Builder {
+	return builder.WhereNotMoreLikeThis(fields, likeSpec, fn)
+}
+
+// WhereNotMoreLikeThis MustNot more_like_this 查询, 查询与给定文本或文档相似的文档
+func (b *Builder) WhereNotMoreLikeThis(fields []string, likeSpec fulltext.LikeSpec, fn fulltext.MoreLikeThisParamFunc) *Builder {
+	b.moreLikeThis(esearch.MustNot, fields, likeSpec, fn)
+
+	return b
+}
+
 // WhereNotNested MustNot 嵌套查询, 例如嵌套 should 语句
 func WhereNotNested(fn NestWhereFunc) *Builder {
 	return builder.WhereNotNested(fn)
@@ -583,6 +891,66 @@ func (b *Builder) OrWhereWildcard(field string, value string, fn termlevel.Wildc
 	return b
 }
 
+// OrWherePrefixParams Should prefix 查询语句, 支持 case_insensitive, rewrite 参数
+func OrWherePrefixParams(field string, value string, fn termlevel.PrefixParamFunc) *Builder {
+	return builder.OrWherePrefixParams(field, value, fn)
+}
+
+// OrWherePrefixParams Should prefix 查询语句, 支持 case_insensitive, rewrite 参数
+func (b *Builder) OrWherePrefixParams(field string, value string, fn termlevel.PrefixParamFunc) *Builder {
+	b.prefixParamsQuery(esearch.Should, field, value, fn)
+
+	return b
+}
+
+// OrWhereFuzzy Should fuzzy 查询语句
+func OrWhereFuzzy(field string, value string, fn termlevel.FuzzyParamFunc) *Builder {
+	return builder.OrWhereFuzzy(field, value, fn)
+}
+
+// OrWhereFuzzy Should fuzzy 查询语句
+func (b *Builder) OrWhereFuzzy(field string, value string, fn termlevel.FuzzyParamFunc) *Builder {
+	b.fuzzy(esearch.Should, field, value, fn)
+
+	return b
+}
+
+// OrWhereIds Should ids 查询语句
+func OrWhereIds(values []string) *Builder {
+	return builder.OrWhereIds(values)
+}
+
+// OrWhereIds Should ids 查询语句
+func (b *Builder) OrWhereIds(values []string) *Builder {
+	b.ids(esearch.Should, values)
+
+	return b
+}
+
+// OrWhereTermsSet Should terms_set 查询语句
+func OrWhereTermsSet(field string, value []any, fn termlevel.TermsSetParamFunc) *Builder {
+	return builder.OrWhereTermsSet(field, value, fn)
+}
+
+// OrWhereTermsSet Should terms_set 查询语句
+func (b *Builder) OrWhereTermsSet(field string, value []any, fn termlevel.TermsSetParamFunc) *Builder {
+	b.termsSet(esearch.Should, field, value, fn)
+
+	return b
+}
+
+// OrWhereMatchNone Should match_none 查询语句
+func OrWhereMatchNone() *Builder {
+	return builder.OrWhereMatchNone()
+}
+
+// OrWhereMatchNone Should match_none 查询语句
+func (b *Builder) OrWhereMatchNone() *Builder {
+	b.append(esearch.Should, termlevel.TermQuery{MatchNone: &termlevel.MatchNone{}})
+
+	return b
+}
+
 // OrWhereIn Should terms 查询语句
 func OrWhereIn(field string, value []any) *Builder {
 	return builder.OrWhereIn(field, value)
@@ -595,6 +963,23 @@ func (b *Builder) OrWhereIn(field string, value []any) *Builder {
 	return b
 }
 
+// OrWhereInLookup Should terms lookup 查询语句, 查询的值为 index 索引中 id 文档的 path 字段
+func OrWhereInLookup(field string, index string, id string, path string, routing string) *Builder {
+	return builder.OrWhereInLookup(field, index, id, path, routing)
+}
+
+// OrWhereInLookup Should terms lookup 查询语句, 查询的值为 index 索引中 id 文档的 path 字段
+func (b *Builder) OrWhereInLookup(field string, index string, id string, path string, routing string) *Builder {
+	b.termsLookupQuery(esearch.Should, field, termlevel.TermsLookup{
+		Index:   index,
+		Id:      id,
+		Path:    path,
+		Routing: routing,
+	})
+
+	return b
+}
+
 // OrWhereBetween Should Range ( gte:>=a and lte:<=b) 查询语句
 func OrWhereBetween(field string, value1, value2 int) *Builder {
 	return builder.OrWhereBetween(field, value1, value2)
@@ -632,6 +1017,17 @@ func (b *Builder) OrWhereMatch(field string, value string, matchType esearch.Mat
 	return b
 }
 
+// OrWhereMatchPhrase Should match_phrase, match_phrase_prefix 匹配, 可以设置 slop
+func OrWhereMatchPhrase(field string, value string, matchType esearch.MatchType, fn fulltext.PhraseParamsFunc) *Builder {
+	return builder.OrWhereMatchPhrase(field, value, matchType, fn)
+}
+
+// OrWhereMatchPhrase Should match_phrase, match_phrase_prefix 匹配, 可以设置 slop
+func (b *Builder) OrWhereMatchPhrase(field string, value string, matchType esearch.MatchType, fn fulltext.PhraseParamsFunc) *Builder {
+	b.whereMatchPhrase(esearch.Should, field, value, matchType, fn)
+	return b
+}
+
 // OrWhereMultiMatch Should multi_match 匹配
 func OrWhereMultiMatch(field []string, value string, fieldType esearch.FieldType, fn fulltext.AppendParamsFunc) *Builder {
 	return builder.OrWhereMultiMatch(field, value, fieldType, fn)
@@ -644,6 +1040,42 @@ func (b *Builder) OrWhereMultiMatch(field []string, value string, fieldType esea
 	return b
 }
 
+// OrWhereQueryString Should query_string 查询, 支持 lucene 语法
+func OrWhereQueryString(query string, fn fulltext.QueryStringParamFunc) *Builder {
+	return builder.OrWhereQueryString(query, fn)
+}
+
+// OrWhereQueryString Should query_string 查询, 支持 lucene 语法
+func (b *Builder) OrWhereQueryString(query string, fn fulltext.QueryStringParamFunc) *Builder {
+	b.queryString(esearch.Should, query, fn)
+
+	return b
+}
+
+// OrWhereSimpleQueryString Should simple_query_string 查询
+func OrWhereSimpleQueryString(query string, fn fulltext.SimpleQueryStringParamFunc) *Builder {
+	return builder.OrWhereSimpleQueryString(query, fn)
+}
+
+// OrWhereSimpleQueryString Should simple_query_string 查询
+func (b *Builder) OrWhereSimpleQueryString(query string, fn fulltext.SimpleQueryStringParamFunc) *Builder {
+	b.simpleQueryString(esearch.Should, query, fn)
+
+	return b
+}
+
+// OrWhereMoreLikeThis Should more_like_this 查询, 查询与给定文本或文档相似的文档
+func OrWhereMoreLikeThis(fields []string, likeSpec fulltext.LikeSpec, fn fulltext.MoreLikeThisParamFunc) *Builder {
+	return builder.OrWhereMoreLikeThis(fields, likeSpec, fn)
+}
+
+// OrWhereMoreLikeThis Should more_like_this 查询, 查询与给定文本或文档相似的文档
+func (b *Builder) OrWhereMoreLikeThis(fields []string, likeSpec fulltext.LikeSpec, fn fulltext.MoreLikeThisParamFunc) *Builder {
+	b.moreLikeThis(esearch.Should, fields, likeSpec, fn)
+
+	return b
+}
+
 // OrWhereNested Should 嵌套查询, 例如嵌套 should 语句
 func OrWhereNested(fn NestWhereFunc) *Builder {
 	return builder.OrWhereNested(fn)
@@ -725,6 +1157,66 @@ func (b *Builder) FilterWildcard(field string, value string, fn termlevel.Wildca
 	return b
 }
 
+// FilterPrefixParams Filter prefix 查询语句, 支持 case_insensitive, rewrite 参数
+func FilterPrefixParams(field string, value string, fn termlevel.PrefixParamFunc) *Builder {
+	return builder.FilterPrefixParams(field, value, fn)
+}
+
+// FilterPrefixParams Filter prefix 查询语句, 支持 case_insensitive, rewrite 参数
+func (b *Builder) FilterPrefixParams(field string, value string, fn termlevel.PrefixParamFunc) *Builder {
+	b.prefixParamsQuery(esearch.FilterClause, field, value, fn)
+
+	return b
+}
+
+// FilterFuzzy Filter fuzzy 查询语句
+func FilterFuzzy(field string, value string, fn termlevel.FuzzyParamFunc) *Builder {
+	return builder.FilterFuzzy(field, value, fn)
+}
+
+// FilterFuzzy Filter fuzzy 查询语句
+func (b *Builder) FilterFuzzy(field string, value string, fn termlevel.FuzzyParamFunc) *Builder {
+	b.fuzzy(esearch.FilterClause, field, value, fn)
+
+	return b
+}
+
+// FilterIds Filter ids 查询语句
+func FilterIds(values []string) *Builder {
+	return builder.FilterIds(values)
+}
+
+// FilterIds Filter ids 查询语句
+func (b *Builder) FilterIds(values []string) *Builder {
+	b.ids(esearch.FilterClause, values)
+
+	return b
+}
+
+// FilterTermsSet Filter terms_set 查询语句
+func FilterTermsSet(field string, value []any, fn termlevel.TermsSetParamFunc) *Builder {
+	return builder.FilterTermsSet(field, value, fn)
+}
+
+// FilterTermsSet Filter terms_set 查询语句
+func (b *Builder) FilterTermsSet(field string, value []any, fn termlevel.TermsSetParamFunc) *Builder {
+	b.termsSet(esearch.FilterClause, field, value, fn)
+
+	return b
+}
+
+// FilterMatchNone Filter match_none 查询语句
+func FilterMatchNone() *Builder {
+	return builder.FilterMatchNone()
+}
+
+// FilterMatchNone Filter match_none 查询语句
+func (b *Builder) FilterMatchNone() *Builder {
+	b.append(esearch.FilterClause, termlevel.TermQuery{MatchNone: &termlevel.MatchNone{}})
+
+	return b
+}
+
 // FilterIn Filter terms 查询语句
 func FilterIn(field string, value []any) *Builder {
 	return builder.WhereIn(field, value)
@@ -737,6 +1229,23 @@ func (b *Builder) FilterIn(field string, value []any) *Builder {
 	return b
 }
 
+// FilterInLookup Filter terms lookup 查询语句, 查询的值为 index 索引中 id 文档的 path 字段
+func FilterInLookup(field string, index string, id string, path string, routing string) *Builder {
+	return builder.FilterInLookup(field, index, id, path, routing)
+}
+
+// FilterInLookup Filter terms lookup 查询语句, 查询的值为 index 索引中 id 文档的 path 字段
+func (b *Builder) FilterInLookup(field string, index string, id string, path string, routing string) *Builder {
+	b.termsLookupQuery(esearch.FilterClause, field, termlevel.TermsLookup{
+		Index:   index,
+		Id:      id,
+		Path:    path,
+		Routing: routing,
+	})
+
+	return b
+}
+
 // FilterBetween Filter Range ( gte:>=a and lte:<=b) 查询语句
 func FilterBetween(field string, value1, value2 int) *Builder {
 	return builder.WhereBetween(field, value1, value2)
@@ -774,6 +1283,17 @@ func (b *Builder) FilterMatch(field string, value string, matchType esearch.Matc
 	return b
 }
 
+// FilterMatchPhrase Filter match_phrase, match_phrase_prefix 匹配, 可以设置 slop
+func FilterMatchPhrase(field string, value string, matchType esearch.MatchType, fn fulltext.PhraseParamsFunc) *Builder {
+	return builder.FilterMatchPhrase(field, value, matchType, fn)
+}
+
+// FilterMatchPhrase Filter match_phrase, match_phrase_prefix 匹配, 可以设置 slop
+func (b *Builder) FilterMatchPhrase(field string, value string, matchType esearch.MatchType, fn fulltext.PhraseParamsFunc) *Builder {
+	b.whereMatchPhrase(esearch.FilterClause, field, value, matchType, fn)
+	return b
+}
+
 // FilterMultiMatch Filter multi_match 匹配
 func FilterMultiMatch(field []string, value string, fieldType esearch.FieldType, fn fulltext.AppendParamsFunc) *Builder {
 	return builder.FilterMultiMatch(field, value, fieldType, fn)
@@ -786,6 +1306,42 @@ func (b *Builder) FilterMultiMatch(field []string, value string, fieldType esear
 	return b
 }
 
+// FilterQueryString Filter query_string 查询, 支持 lucene 语法
+func FilterQueryString(query string, fn fulltext.QueryStringParamFunc) *Builder {
+	return builder.FilterQueryString(query, fn)
+}
+
+// FilterQueryString Filter query_string 查询, 支持 lucene 语法
+func (b *Builder) FilterQueryString(query string, fn fulltext.QueryStringParamFunc) *Builder {
+	b.queryString(esearch.FilterClause, query, fn)
+
+	return b
+}
+
+// FilterSimpleQueryString Filter simple_query_string 查询
+func FilterSimpleQueryString(query string, fn fulltext.SimpleQueryStringParamFunc) *Builder {
+	return builder.FilterSimpleQueryString(query, fn)
+}
+
+// FilterSimpleQueryString Filter simple_query_string 查询
+func (b *Builder) FilterSimpleQueryString(query string, fn fulltext.SimpleQueryStringParamFunc) *Builder {
+	b.simpleQueryString(esearch.FilterClause, query, fn)
+
+	return b
+}
+
+// FilterMoreLikeThis Filter more_like_this 查询, 查询与给定文本或文档相似的文档
+func FilterMoreLikeThis(fields []string, likeSpec fulltext.LikeSpec, fn fulltext.MoreLikeThisParamFunc) *Builder {
+	return builder.FilterMoreLikeThis(fields, likeSpec, fn)
+}
+
+// FilterMoreLikeThis Filter more_like_this 查询, 查询与给定文本或文档相似的文档
+func (b *Builder) FilterMoreLikeThis(fields []string, likeSpec fulltext.LikeSpec, fn fulltext.MoreLikeThisParamFunc) *Builder {
+	b.moreLikeThis(esearch.FilterClause, fields, likeSpec, fn)
+
+	return b
+}
+
 // FilterNested Filter 嵌套查询, 例如嵌套 should 语句
 func FilterNested(fn NestWhereFunc) *Builder {
 	return builder.FilterNested(fn)
@@ -835,6 +1391,20 @@ func (b *Builder) termsQuery(clauseTyp esearch.BoolClauseType, field string, val
 	}
 }
 
+func (b *Builder) termsLookupQuery(clauseTyp esearch.BoolClauseType, field string, lookup termlevel.TermsLookup) {
+	if lookup.Index == "" || lookup.Id == "" || lookup.Path == "" {
+		return
+	}
+
+	terms := termlevel.TermsLookupQuery{
+		Terms: make(map[string]termlevel.TermsLookup),
+	}
+
+	terms.Terms[field] = lookup
+
+	b.append(clauseTyp, terms)
+}
+
 func (b *Builder) exists(clauseTyp esearch.BoolClauseType, field string) {
 	term := termlevel.TermQuery{
 		Exists: make(map[string]string),
@@ -873,6 +1443,64 @@ func (b *Builder) wildcard(clauseTyp esearch.BoolClauseType, field string, value
 	b.append(clauseTyp, term)
 }
 
+func (b *Builder) prefixParamsQuery(clauseTyp esearch.BoolClauseType, field string, value string, fn termlevel.PrefixParamFunc) {
+	term := termlevel.TermQuery{
+		Prefix: make(map[string]any),
+	}
+
+	prefix := termlevel.Prefix{Value: value}
+	if fn != nil {
+		prefix.PrefixParam = fn()
+	}
+	term.Prefix[field] = prefix
+
+	b.append(clauseTyp, term)
+}
+
+func (b *Builder) fuzzy(clauseTyp esearch.BoolClauseType, field string, value string, fn termlevel.FuzzyParamFunc) {
+	term := termlevel.TermQuery{
+		Fuzzy: make(map[string]termlevel.Fuzzy),
+	}
+
+	fuzzy := termlevel.Fuzzy{Value: value}
+	if fn != nil {
+		fuzzy.FuzzyParam = fn()
+	}
+	term.Fuzzy[field] = fuzzy
+
+	b.append(clauseTyp, term)
+}
+
+func (b *Builder) ids(clauseTyp esearch.BoolClauseType, values []string) {
+	if len(values) == 0 {
+		return
+	}
+
+	term := termlevel.TermQuery{
+		Ids: &termlevel.Ids{Values: values},
+	}
+
+	b.append(clauseTyp, term)
+}
+
+func (b *Builder) termsSet(clauseTyp esearch.BoolClauseType, field string, value []any, fn termlevel.TermsSetParamFunc) {
+	if len(value) == 0 || !checkType(value[0]) {
+		return
+	}
+
+	term := termlevel.TermQuery{
+		TermsSet: make(map[string]termlevel.TermsSet),
+	}
+
+	termsSet := termlevel.TermsSet{Terms: value}
+	if fn != nil {
+		termsSet.TermsSetParam = fn()
+	}
+	term.TermsSet[field] = termsSet
+
+	b.append(clauseTyp, term)
+}
+
 func (b *Builder) whereRange(clauseTyp esearch.BoolClauseType, field string, value any, rangeType esearch.RangeType) {
 	if !checkType(value) {
 		return
@@ -943,6 +1571,29 @@ func (b *Builder) whereMatch(clauseTyp esearch.BoolClauseType, field string, val
 	b.append(clauseTyp, textQuery)
 }
 
+// whereMatchPhrase matchType 只能是 esearch.MatchPhrase, esearch.MatchPhrasePrefix
+func (b *Builder) whereMatchPhrase(clauseTyp esearch.BoolClauseType, field string, value string, matchType esearch.MatchType, fn fulltext.PhraseParamsFunc) {
+	if matchType != esearch.MatchPhrase && matchType != esearch.MatchPhrasePrefix {
+		return
+	}
+
+	matchQuery := fulltext.MatchQuery{Query: value}
+	if fn != nil {
+		params := fn()
+		matchQuery.AppendParams = params.AppendParams
+		matchQuery.Slop = params.Slop
+	}
+
+	textQuery := fulltext.TextQuery{}
+	if matchType == esearch.MatchPhrase {
+		textQuery.MatchPhrase = map[string]fulltext.MatchQuery{field: matchQuery}
+	} else {
+		textQuery.MatchPhrasePrefix = map[string]fulltext.MatchQuery{field: matchQuery}
+	}
+
+	b.append(clauseTyp, textQuery)
+}
+
 func (b *Builder) whereMultiMatch(clauseTyp esearch.BoolClauseType, field []string, value string, fieldType esearch.FieldType, fn fulltext.AppendParamsFunc) {
 	if field == nil || len(field) == 0 {
 		return
@@ -969,6 +1620,48 @@ func (b *Builder) whereMultiMatch(clauseTyp esearch.BoolClauseType, field []stri
 	b.append(clauseTyp, textQuery)
 }
 
+func (b *Builder) queryString(clauseTyp esearch.BoolClauseType, query string, fn fulltext.QueryStringParamFunc) {
+	if query == "" {
+		return
+	}
+
+	queryString := &fulltext.QueryStringQuery{Query: query}
+	if fn != nil {
+		queryString.QueryStringParam = fn()
+	}
+
+	b.append(clauseTyp, fulltext.TextQuery{QueryString: queryString})
+}
+
+func (b *Builder) simpleQueryString(clauseTyp esearch.BoolClauseType, query string, fn fulltext.SimpleQueryStringParamFunc) {
+	if query == "" {
+		return
+	}
+
+	simpleQueryString := &fulltext.SimpleQueryStringQuery{Query: query}
+	if fn != nil {
+		simpleQueryString.SimpleQueryStringParam = fn()
+	}
+
+	b.append(clauseTyp, fulltext.TextQuery{SimpleQueryString: simpleQueryString})
+}
+
+func (b *Builder) moreLikeThis(clauseTyp esearch.BoolClauseType, fields []string, likeSpec fulltext.LikeSpec, fn fulltext.MoreLikeThisParamFunc) {
+	if len(likeSpec.Like) == 0 {
+		return
+	}
+
+	moreLikeThis := &fulltext.MoreLikeThisQuery{
+		Fields:   fields,
+		LikeSpec: likeSpec,
+	}
+	if fn != nil {
+		moreLikeThis.MoreLikeThisParam = fn()
+	}
+
+	b.append(clauseTyp, fulltext.TextQuery{MoreLikeThis: moreLikeThis})
+}
+
 func (b *Builder) append(clauseTyp esearch.BoolClauseType, clause esearch.BoolBuilder) {
 	b.where[clauseTyp] = append(b.where[clauseTyp], clause)
 }
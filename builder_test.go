@@ -0,0 +1,212 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"github.com/KingSolvewer/elasticsearch-query-builder/fulltext"
+	"github.com/KingSolvewer/elasticsearch-query-builder/termlevel"
+)
+
+func TestQueryString(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "query_string",
+			b: NewBuilder().WhereQueryString("title:(quick OR brown)", func() fulltext.QueryStringParam {
+				return fulltext.QueryStringParam{DefaultOperator: fulltext.And, Fields: []string{fulltext.FieldBoost("title", 3), "content"}}
+			}),
+			want: `{"query":{"bool":{"must":[{"query_string":{"query":"title:(quick OR brown)","fields":["title^3","content"],"default_operator":"AND"}}]}}}`,
+		},
+		{
+			name: "simple_query_string with flags",
+			b: NewBuilder().FilterSimpleQueryString("foo + bar", func() fulltext.SimpleQueryStringParam {
+				return fulltext.SimpleQueryStringParam{Flags: fulltext.Flags(fulltext.FlagOr, fulltext.FlagAnd)}
+			}),
+			want: `{"query":{"bool":{"filter":[{"simple_query_string":{"query":"foo + bar","flags":"OR|AND"}}]}}}`,
+		},
+		{
+			name: "escaped user input",
+			b:    NewBuilder().WhereNotQueryString(fulltext.EscapeQueryString("a:b"), nil),
+			want: `{"query":{"bool":{"must_not":[{"query_string":{"query":"a\\:b"}}]}}}`,
+		},
+		{
+			name: "empty query is skipped",
+			b:    NewBuilder().OrWhereQueryString("", nil).OrWhereSimpleQueryString("", nil),
+			want: `{"query":{"match_all":{}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestTermLevelQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "prefix with params",
+			b: NewBuilder().WherePrefixParams("user", "ki", func() termlevel.PrefixParam {
+				return termlevel.PrefixParam{CaseInsensitive: true}
+			}),
+			want: `{"query":{"bool":{"must":[{"prefix":{"user":{"value":"ki","case_insensitive":true}}}]}}}`,
+		},
+		{
+			name: "fuzzy",
+			b: NewBuilder().OrWhereFuzzy("user", "ki", func() termlevel.FuzzyParam {
+				return termlevel.FuzzyParam{Fuzziness: "AUTO"}
+			}),
+			want: `{"query":{"bool":{"should":[{"fuzzy":{"user":{"value":"ki","fuzziness":"AUTO"}}}]}}}`,
+		},
+		{
+			name: "ids",
+			b:    NewBuilder().FilterIds([]string{"1", "2"}),
+			want: `{"query":{"bool":{"filter":[{"ids":{"values":["1","2"]}}]}}}`,
+		},
+		{
+			name: "terms_set",
+			b: NewBuilder().WhereTermsSet("tags", []any{"a", "b"}, func() termlevel.TermsSetParam {
+				return termlevel.TermsSetParam{MinimumShouldMatchField: "required"}
+			}),
+			want: `{"query":{"bool":{"must":[{"terms_set":{"tags":{"terms":["a","b"],"minimum_should_match_field":"required"}}}]}}}`,
+		},
+		{
+			name: "match_none",
+			b:    NewBuilder().WhereNotMatchNone(),
+			want: `{"query":{"bool":{"must_not":[{"match_none":{}}]}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestTermsLookup(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "must",
+			b:    NewBuilder().WhereInLookup("user", "users", "2", "followers", ""),
+			want: `{"query":{"bool":{"must":[{"terms":{"user":{"index":"users","id":"2","path":"followers"}}}]}}}`,
+		},
+		{
+			name: "filter with routing",
+			b:    NewBuilder().FilterInLookup("user", "users", "2", "followers", "r1"),
+			want: `{"query":{"bool":{"filter":[{"terms":{"user":{"index":"users","id":"2","path":"followers","routing":"r1"}}}]}}}`,
+		},
+		{
+			name: "must_not and should",
+			b:    NewBuilder().WhereNotInLookup("user", "users", "2", "blocked", "").OrWhereInLookup("user", "users", "3", "followers", ""),
+			want: `{"query":{"bool":{"must_not":[{"terms":{"user":{"index":"users","id":"2","path":"blocked"}}}],"should":[{"terms":{"user":{"index":"users","id":"3","path":"followers"}}}]}}}`,
+		},
+		{
+			name: "missing index is skipped",
+			b:    NewBuilder().WhereInLookup("user", "", "2", "followers", ""),
+			want: `{"query":{"match_all":{}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestMoreLikeThis(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "text and document",
+			b: NewBuilder().WhereMoreLikeThis([]string{"title", "content"}, fulltext.LikeSpec{
+				Like:   []fulltext.Liker{fulltext.LikeText("中国电信"), fulltext.LikeDocument{Index: "news", Id: "1"}},
+				Unlike: []fulltext.Liker{fulltext.LikeText("广告")},
+			}, func() fulltext.MoreLikeThisParam {
+				return fulltext.MoreLikeThisParam{MinTermFreq: 1, MaxQueryTerms: 12}
+			}),
+			want: `{"query":{"bool":{"must":[{"more_like_this":{"fields":["title","content"],"like":["中国电信",{"_index":"news","_id":"1"}],"unlike":["广告"],"min_term_freq":1,"max_query_terms":12}}]}}}`,
+		},
+		{
+			name: "artificial document",
+			b: NewBuilder().FilterMoreLikeThis(nil, fulltext.LikeSpec{
+				Like: []fulltext.Liker{fulltext.LikeDocument{Index: "news", Doc: map[string]any{"title": "5G"}}},
+			}, nil),
+			want: `{"query":{"bool":{"filter":[{"more_like_this":{"like":[{"_index":"news","doc":{"title":"5G"}}]}}]}}}`,
+		},
+		{
+			name: "empty like is skipped",
+			b:    NewBuilder().WhereMoreLikeThis(nil, fulltext.LikeSpec{}, nil),
+			want: `{"query":{"match_all":{}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestMatchPhraseSlop(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "match has no slop",
+			b: NewBuilder().WhereMatch("title", "中国", esearch.Match, func() fulltext.AppendParams {
+				return fulltext.AppendParams{}
+			}),
+			want: `{"query":{"bool":{"must":[{"match":{"title":{"query":"中国"}}}]}}}`,
+		},
+		{
+			name: "match_phrase with slop",
+			b: NewBuilder().WhereMatchPhrase("title", "中国 电信", esearch.MatchPhrase, func() fulltext.PhraseParams {
+				return fulltext.PhraseParams{Slop: 2}
+			}),
+			want: `{"query":{"bool":{"must":[{"match_phrase":{"title":{"query":"中国 电信","slop":2}}}]}}}`,
+		},
+		{
+			name: "match_phrase_prefix with slop",
+			b: NewBuilder().WhereMatchPhrase("title", "中国 电", esearch.MatchPhrasePrefix, func() fulltext.PhraseParams {
+				return fulltext.PhraseParams{Slop: 2}
+			}),
+			want: `{"query":{"bool":{"must":[{"match_phrase_prefix":{"title":{"query":"中国 电","slop":2}}}]}}}`,
+		},
+		{
+			name: "match_phrase_prefix through WhereMatch",
+			b:    NewBuilder().WhereMatch("title", "中国 电", esearch.MatchPhrasePrefix, nil),
+			want: `{"query":{"bool":{"must":[{"match_phrase_prefix":{"title":{"query":"中国 电"}}}]}}}`,
+		},
+		{
+			name: "non phrase match type is skipped",
+			b:    NewBuilder().WhereMatchPhrase("title", "中国 电信", esearch.Match, nil),
+			want: `{"query":{"match_all":{}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package elastic
+
+import (
+	"fmt"
+	"github.com/KingSolvewer/elasticsearch-query-builder/aggs"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"github.com/KingSolvewer/elasticsearch-query-builder/parser"
+	"github.com/valyala/fastjson"
+)
+
+// CompositeSearchFunc 使用 b.Dsl() 执行查询, 返回 es 的原始响应
+type CompositeSearchFunc func(b *Builder) ([]byte, error)
+
+// CompositeBucketFunc 返回 error 时停止遍历
+type CompositeBucketFunc func(bucket esearch.Bucket) error
+
+// CompositeEach 按照 after_key 翻页遍历 Composite 聚合的全部 bucket, 每次查询前把上一页的 after_key 设置为聚合的 after,
+// 响应中没有 after_key 或者 bucket 为空时结束. dest 用于解析子聚合中的 top_hits, 遍历结束后恢复聚合原来的 after
+func (b *Builder) CompositeEach(name string, search CompositeSearchFunc, dest any, fn CompositeBucketFunc) error {
+	aggField := name + esearch.Composite
+
+	aggregation, ok := b.aggregations[aggField]
+	if !ok {
+		return fmt.Errorf("composite aggregation %s is not existing", name)
+	}
+
+	compositeAggs, ok := aggregation.Params.(*aggs.CompositeAggs)
+	if !ok {
+		return fmt.Errorf("%s is not a composite aggregation", aggField)
+	}
+
+	defer func() {
+		b.aggregations[aggField] = aggregation
+	}()
+
+	for {
+		body, err := search(b)
+		if err != nil {
+			return err
+		}
+
+		value, err := fastjson.ParseBytes(body)
+		if err != nil {
+			return err
+		}
+
+		aggsObj := value.GetObject("aggregations")
+		if aggsObj == nil {
+			return nil
+		}
+
+		aggsResult, errorSet := parser.AggValueParser(aggsObj, dest)
+		if len(errorSet) > 0 {
+			return errorSet[0]
+		}
+
+		compositeResult, ok := aggsResult.Composite[aggField]
+		if !ok {
+			return nil
+		}
+
+		for _, bucket := range compositeResult.Buckets {
+			if err = fn(bucket); err != nil {
+				return err
+			}
+		}
+
+		if len(compositeResult.Buckets) == 0 || len(compositeResult.AfterKey) == 0 {
+			return nil
+		}
+
+		nextAggs := *compositeAggs
+		nextAggs.After = compositeResult.AfterKey
+		nextAggregation := *aggregation
+		nextAggregation.Params = &nextAggs
+		b.aggregations[aggField] = &nextAggregation
+	}
+}
@@ -0,0 +1,127 @@
+package elastic
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/aggs"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+func TestComposite(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "all source types with after",
+			b: NewBuilder().Size(0).Composite("page", []aggs.CompositeSource{
+				aggs.TermsSource{Name: "platform", Field: "platform"},
+				aggs.HistogramSource{Name: "price", Field: "price", Interval: 10},
+				aggs.DateHistogramSource{Name: "day", Field: "date", CalendarInterval: "1d", CompositeSourceParam: aggs.CompositeSourceParam{Order: esearch.Desc}},
+				aggs.GeoTileGridSource{Name: "tile", Field: "location", Precision: 8},
+			}, 100, map[string]any{"platform": "web"}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"page_composite":{"composite":{"size":100,"sources":[{"platform":{"terms":{"field":"platform"}}},{"price":{"histogram":{"field":"price","interval":10}}},{"day":{"date_histogram":{"field":"date","calendar_interval":"1d","order":"desc"}}},{"tile":{"geotile_grid":{"field":"location","precision":8}}}],"after":{"platform":"web"}}}}}`,
+		},
+		{
+			name: "no sources is skipped",
+			b:    NewBuilder().Size(0).Composite("page", nil, 100, nil),
+			want: `{"size":0,"query":{"match_all":{}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestCompositeEmptySourceNamePanics(t *testing.T) {
+	assertPanic(t, func() {
+		NewBuilder().Composite("page", []aggs.CompositeSource{aggs.TermsSource{Field: "platform"}}, 10, nil)
+	})
+}
+
+func TestCompositeEach(t *testing.T) {
+	pages := []string{
+		`{"aggregations":{"page_composite":{"after_key":{"id":9007199254740993},"buckets":[{"key":{"id":1},"doc_count":2},{"key":{"id":9007199254740993},"doc_count":1}]}}}`,
+		`{"aggregations":{"page_composite":{"buckets":[]}}}`,
+	}
+
+	tests := []struct {
+		name      string
+		stopAfter int
+		wantDocs  []int
+		wantAfter []string
+		wantErr   bool
+	}{
+		{
+			name:      "until exhausted",
+			wantDocs:  []int{2, 1},
+			wantAfter: []string{"", `{"id":9007199254740993}`},
+		},
+		{
+			name:      "callback error stops",
+			stopAfter: 1,
+			wantDocs:  []int{2},
+			wantAfter: []string{""},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBuilder().Size(0).Composite("page", []aggs.CompositeSource{aggs.TermsSource{Name: "id", Field: "id"}}, 2, nil)
+
+			afters := make([]string, 0)
+			search := func(b *Builder) ([]byte, error) {
+				after := b.aggregations["page"+esearch.Composite].Params.(*aggs.CompositeAggs).After
+				if after == nil {
+					afters = append(afters, "")
+				} else {
+					body, _ := json.Marshal(after)
+					afters = append(afters, string(body))
+				}
+
+				return []byte(pages[len(afters)-1]), nil
+			}
+
+			docs := make([]int, 0)
+			err := b.CompositeEach("page", search, nil, func(bucket esearch.Bucket) error {
+				docs = append(docs, bucket.DocCount)
+				if len(docs) == tt.stopAfter {
+					return errors.New("stop")
+				}
+				return nil
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CompositeEach() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(docs, tt.wantDocs) {
+				t.Errorf("doc counts = %v, want %v", docs, tt.wantDocs)
+			}
+			if !reflect.DeepEqual(afters, tt.wantAfter) {
+				t.Errorf("afters = %v, want %v", afters, tt.wantAfter)
+			}
+			if after := b.aggregations["page"+esearch.Composite].Params.(*aggs.CompositeAggs).After; after != nil {
+				t.Errorf("after is not restored, got %v", after)
+			}
+		})
+	}
+}
+
+func TestCompositeEachMissingAggregation(t *testing.T) {
+	err := NewBuilder().CompositeEach("page", func(b *Builder) ([]byte, error) {
+		return nil, nil
+	}, nil, func(bucket esearch.Bucket) error {
+		return nil
+	})
+	if err == nil {
+		t.Errorf("CompositeEach() expected error for missing composite aggregation")
+	}
+}
@@ -0,0 +1,409 @@
+package elastic
+
+import (
+	"github.com/KingSolvewer/elasticsearch-query-builder/compound"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+type functionScore struct {
+	fn        NestWhereFunc
+	functions []compound.ScoreFunction
+	param     compound.FunctionScoreParam
+}
+
+type scriptScore struct {
+	fn     NestWhereFunc
+	script esearch.Script
+	param  compound.ScriptScoreParam
+}
+
+// NestQuery 将闭包中的查询条件编译成 bool 查询, 用于评分函数的 filter 等需要 esearch.Query 的参数
+func NestQuery(fn NestWhereFunc) esearch.Query {
+	return builder.subQuery(fn)
+}
+
+// FunctionScore function_score 查询, 使用评分函数包装 Builder 生成的查询, fn 中的条件会追加到被包装查询的 must 语句中.
+// 重复调用时替换之前的 fn 和评分函数, FunctionScoreParams 设置的参数保留
+func FunctionScore(fn NestWhereFunc, functions ...compound.ScoreFunction) *Builder {
+	return builder.FunctionScore(fn, functions...)
+}
+
+// FunctionScore function_score 查询, 使用评分函数包装 Builder 生成的查询, fn 中的条件会追加到被包装查询的 must 语句中.
+// 重复调用时替换之前的 fn 和评分函数, FunctionScoreParams 设置的参数保留
+func (b *Builder) FunctionScore(fn NestWhereFunc, functions ...compound.ScoreFunction) *Builder {
+	newFunctionScore := &functionScore{
+		fn:        fn,
+		functions: append([]compound.ScoreFunction(nil), functions...),
+	}
+	if b.functionScore != nil {
+		newFunctionScore.param = b.functionScore.param
+	}
+
+	b.functionScore = newFunctionScore
+
+	return b
+}
+
+// FunctionScoreParams 设置 function_score 的 score_mode, boost_mode, max_boost, min_score
+func FunctionScoreParams(fn compound.FunctionScoreParamFunc) *Builder {
+	return builder.FunctionScoreParams(fn)
+}
+
+// FunctionScoreParams 设置 function_score 的 score_mode, boost_mode, max_boost, min_score
+func (b *Builder) FunctionScoreParams(fn compound.FunctionScoreParamFunc) *Builder {
+	if fn == nil {
+		return b
+	}
+
+	newFunctionScore := &functionScore{}
+	if b.functionScore != nil {
+		*newFunctionScore = *b.functionScore
+	}
+	newFunctionScore.param = fn()
+
+	b.functionScore = newFunctionScore
+
+	return b
+}
+
+// ScriptScore script_score 查询, 使用脚本计算 Builder 生成的查询的得分, fn 中的条件会追加到被包装查询的 must 语句中
+func ScriptScore(fn NestWhereFunc, script esearch.Script, paramFn compound.ScriptScoreParamFunc) *Builder {
+	return builder.ScriptScore(fn, script, paramFn)
+}
+
+// ScriptScore script_score 查询, 使用脚本计算 Builder 生成的查询的得分, fn 中的条件会追加到被包装查询的 must 语句中
+func (b *Builder) ScriptScore(fn NestWhereFunc, script esearch.Script, paramFn compound.ScriptScoreParamFunc) *Builder {
+	b.scriptScore = &scriptScore{
+		fn:     fn,
+		script: script,
+	}
+
+	if paramFn != nil {
+		b.scriptScore.param = paramFn()
+	}
+
+	return b
+}
+
+// DisMax dis_max 查询, 作为最外层的查询语句, Builder 中存在其他查询条件时, 追加到 bool 查询的 must 语句中
+func DisMax(tieBreaker float64, fns ...NestWhereFunc) *Builder {
+	return builder.DisMax(tieBreaker, fns...)
+}
+
+// DisMax dis_max 查询, 作为最外层的查询语句, Builder 中存在其他查询条件时, 追加到 bool 查询的 must 语句中
+func (b *Builder) DisMax(tieBreaker float64, fns ...NestWhereFunc) *Builder {
+	query := b.disMax(tieBreaker, fns...)
+	if query != nil {
+		b.compoundQuery = query
+	}
+
+	return b
+}
+
+// WhereDisMax Must dis_max 查询
+func WhereDisMax(tieBreaker float64, fns ...NestWhereFunc) *Builder {
+	return builder.WhereDisMax(tieBreaker, fns...)
+}
+
+// WhereDisMax Must dis_max 查询
+func (b *Builder) WhereDisMax(tieBreaker float64, fns ...NestWhereFunc) *Builder {
+	query := b.disMax(tieBreaker, fns...)
+	if query != nil {
+		b.append(esearch.Must, query)
+	}
+
+	return b
+}
+
+// WhereNotDisMax MustNot dis_max 查询
+func WhereNotDisMax(tieBreaker float64, fns ...NestWhereFunc) *Builder {
+	return builder.WhereNotDisMax(tieBreaker, fns...)
+}
+
+// WhereNotDisMax MustNot dis_max 查询
+func (b *Builder) WhereNotDisMax(tieBreaker float64, fns ...NestWhereFunc) *Builder {
+	query := b.disMax(tieBreaker, fns...)
+	if query != nil {
+		b.append(esearch.MustNot, query)
+	}
+
+	return b
+}
+
+// OrWhereDisMax Should dis_max 查询
+func OrWhereDisMax(tieBreaker float64, fns ...NestWhereFunc) *Builder {
+	return builder.OrWhereDisMax(tieBreaker, fns...)
+}
+
+// OrWhereDisMax Should dis_max 查询
+func (b *Builder) OrWhereDisMax(tieBreaker float64, fns ...NestWhereFunc) *Builder {
+	query := b.disMax(tieBreaker, fns...)
+	if query != nil {
+		b.append(esearch.Should, query)
+	}
+
+	return b
+}
+
+// FilterDisMax Filter dis_max 查询
+func FilterDisMax(tieBreaker float64, fns ...NestWhereFunc) *Builder {
+	return builder.FilterDisMax(tieBreaker, fns...)
+}
+
+// FilterDisMax Filter dis_max 查询
+func (b *Builder) FilterDisMax(tieBreaker float64, fns ...NestWhereFunc) *Builder {
+	query := b.disMax(tieBreaker, fns...)
+	if query != nil {
+		b.append(esearch.FilterClause, query)
+	}
+
+	return b
+}
+
+// Boosting boosting 查询, 作为最外层的查询语句, Builder 中存在其他查询条件时, 追加到 bool 查询的 must 语句中
+func Boosting(positiveFn, negativeFn NestWhereFunc, negativeBoost float64) *Builder {
+	return builder.Boosting(positiveFn, negativeFn, negativeBoost)
+}
+
+// Boosting boosting 查询, 作为最外层的查询语句, Builder 中存在其他查询条件时, 追加到 bool 查询的 must 语句中
+func (b *Builder) Boosting(positiveFn, negativeFn NestWhereFunc, negativeBoost float64) *Builder {
+	query := b.boosting(positiveFn, negativeFn, negativeBoost)
+	if query != nil {
+		b.compoundQuery = query
+	}
+
+	return b
+}
+
+// WhereBoosting Must boosting 查询
+func WhereBoosting(positiveFn, negativeFn NestWhereFunc, negativeBoost float64) *Builder {
+	return builder.WhereBoosting(positiveFn, negativeFn, negativeBoost)
+}
+
+// WhereBoosting Must boosting 查询
+func (b *Builder) WhereBoosting(positiveFn, negativeFn NestWhereFunc, negativeBoost float64) *Builder {
+	query := b.boosting(positiveFn, negativeFn, negativeBoost)
+	if query != nil {
+		b.append(esearch.Must, query)
+	}
+
+	return b
+}
+
+// WhereNotBoosting MustNot boosting 查询
+func WhereNotBoosting(positiveFn, negativeFn NestWhereFunc, negativeBoost float64) *Builder {
+	return builder.WhereNotBoosting(positiveFn, negativeFn, negativeBoost)
+}
+
+// WhereNotBoosting MustNot boosting 查询
+func (b *Builder) WhereNotBoosting(positiveFn, negativeFn NestWhereFunc, negativeBoost float64) *Builder {
+	query := b.boosting(positiveFn, negativeFn, negativeBoost)
+	if query != nil {
+		b.append(esearch.MustNot, query)
+	}
+
+	return b
+}
+
+// OrWhereBoosting Should boosting 查询
+func OrWhereBoosting(positiveFn, negativeFn NestWhereFunc, negativeBoost float64) *Builder {
+	return builder.OrWhereBoosting(positiveFn, negativeFn, negativeBoost)
+}
+
+// OrWhereBoosting Should boosting 查询
+func (b *Builder) OrWhereBoosting(positiveFn, negativeFn NestWhereFunc, negativeBoost float64) *Builder {
+	query := b.boosting(positiveFn, negativeFn, negativeBoost)
+	if query != nil {
+		b.append(esearch.Should, query)
+	}
+
+	return b
+}
+
+// FilterBoosting Filter boosting 查询
+func FilterBoosting(positiveFn, negativeFn NestWhereFunc, negativeBoost float64) *Builder {
+	return builder.FilterBoosting(positiveFn, negativeFn, negativeBoost)
+}
+
+// FilterBoosting Filter boosting 查询
+func (b *Builder) FilterBoosting(positiveFn, negativeFn NestWhereFunc, negativeBoost float64) *Builder {
+	query := b.boosting(positiveFn, negativeFn, negativeBoost)
+	if query != nil {
+		b.append(esearch.FilterClause, query)
+	}
+
+	return b
+}
+
+// ConstantScore constant_score 查询, 作为最外层的查询语句, Builder 中存在其他查询条件时, 追加到 bool 查询的 must 语句中
+func ConstantScore(fn NestWhereFunc, boost float64) *Builder {
+	return builder.ConstantScore(fn, boost)
+}
+
+// ConstantScore constant_score 查询, 作为最外层的查询语句, Builder 中存在其他查询条件时, 追加到 bool 查询的 must 语句中
+func (b *Builder) ConstantScore(fn NestWhereFunc, boost float64) *Builder {
+	query := b.constantScore(fn, boost)
+	if query != nil {
+		b.compoundQuery = query
+	}
+
+	return b
+}
+
+// WhereConstantScore Must constant_score 查询
+func WhereConstantScore(fn NestWhereFunc, boost float64) *Builder {
+	return builder.WhereConstantScore(fn, boost)
+}
+
+// WhereConstantScore Must constant_score 查询
+func (b *Builder) WhereConstantScore(fn NestWhereFunc, boost float64) *Builder {
+	query := b.constantScore(fn, boost)
+	if query != nil {
+		b.append(esearch.Must, query)
+	}
+
+	return b
+}
+
+// WhereNotConstantScore MustNot constant_score 查询
+func WhereNotConstantScore(fn NestWhereFunc, boost float64) *Builder {
+	return builder.WhereNotConstantScore(fn, boost)
+}
+
+// WhereNotConstantScore MustNot constant_score 查询
+func (b *Builder) WhereNotConstantScore(fn NestWhereFunc, boost float64) *Builder {
+	query := b.constantScore(fn, boost)
+	if query != nil {
+		b.append(esearch.MustNot, query)
+	}
+
+	return b
+}
+
+// OrWhereConstantScore Should constant_score 查询
+func OrWhereConstantScore(fn NestWhereFunc, boost float64) *Builder {
+	return builder.OrWhereConstantScore(fn, boost)
+}
+
+// OrWhereConstantScore Should constant_score 查询
+func (b *Builder) OrWhereConstantScore(fn NestWhereFunc, boost float64) *Builder {
+	query := b.constantScore(fn, boost)
+	if query != nil {
+		b.append(esearch.Should, query)
+	}
+
+	return b
+}
+
+// FilterConstantScore Filter constant_score 查询
+func FilterConstantScore(fn NestWhereFunc, boost float64) *Builder {
+	return builder.FilterConstantScore(fn, boost)
+}
+
+// FilterConstantScore Filter constant_score 查询
+func (b *Builder) FilterConstantScore(fn NestWhereFunc, boost float64) *Builder {
+	query := b.constantScore(fn, boost)
+	if query != nil {
+		b.append(esearch.FilterClause, query)
+	}
+
+	return b
+}
+
+func (b *Builder) disMax(tieBreaker float64, fns ...NestWhereFunc) esearch.Query {
+	queries := make([]esearch.Query, 0, len(fns))
+	for _, fn := range fns {
+		if fn != nil {
+			queries = append(queries, b.subQuery(fn))
+		}
+	}
+
+	if len(queries) == 0 {
+		return nil
+	}
+
+	query := make(esearch.Query)
+	query["dis_max"] = &compound.DisMax{
+		Queries:    queries,
+		TieBreaker: tieBreaker,
+	}
+
+	return query
+}
+
+func (b *Builder) boosting(positiveFn, negativeFn NestWhereFunc, negativeBoost float64) esearch.Query {
+	if positiveFn == nil || negativeFn == nil {
+		return nil
+	}
+
+	query := make(esearch.Query)
+	query["boosting"] = &compound.Boosting{
+		Positive:      b.subQuery(positiveFn),
+		Negative:      b.subQuery(negativeFn),
+		NegativeBoost: negativeBoost,
+	}
+
+	return query
+}
+
+func (b *Builder) constantScore(fn NestWhereFunc, boost float64) esearch.Query {
+	if fn == nil {
+		return nil
+	}
+
+	query := make(esearch.Query)
+	query["constant_score"] = &compound.ConstantScore{
+		Filter: b.subQuery(fn),
+		Boost:  boost,
+	}
+
+	return query
+}
+
+// scoreQuery 使用 function_score, script_score 包装查询语句, 同时设置时 script_score 在最外层
+func (b *Builder) scoreQuery(query esearch.Query) esearch.Query {
+	if b.functionScore != nil {
+		newQuery := make(esearch.Query)
+		newQuery["function_score"] = &compound.FunctionScore{
+			Query:              b.mergeQuery(query, b.functionScore.fn),
+			Functions:          b.functionScore.functions,
+			FunctionScoreParam: b.functionScore.param,
+		}
+		query = newQuery
+	}
+
+	if b.scriptScore != nil {
+		newQuery := make(esearch.Query)
+		newQuery["script_score"] = &compound.ScriptScore{
+			Query:            b.mergeQuery(query, b.scriptScore.fn),
+			Script:           b.scriptScore.script,
+			ScriptScoreParam: b.scriptScore.param,
+		}
+		query = newQuery
+	}
+
+	return query
+}
+
+// mergeQuery 将闭包中的条件追加到 bool 查询的 must 语句中, 非 bool 查询时, 与闭包中的条件组合成新的 bool 查询
+func (b *Builder) mergeQuery(query esearch.Query, fn NestWhereFunc) esearch.Query {
+	if fn == nil {
+		return query
+	}
+
+	if boolQuery, ok := query["bool"].(*esearch.BoolQuery); ok {
+		boolQuery.Must = append(boolQuery.Must, b.subQuery(fn))
+		return query
+	}
+
+	if _, ok := query["match_all"]; ok {
+		return b.subQuery(fn)
+	}
+
+	newQuery := make(esearch.Query)
+	newQuery["bool"] = &esearch.BoolQuery{
+		Must: []esearch.BoolBuilder{query, b.subQuery(fn)},
+	}
+
+	return newQuery
+}
@@ -0,0 +1,216 @@
+package compound
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"strings"
+)
+
+type ScoreMode string
+
+const (
+	ScoreMultiply ScoreMode = "multiply"
+	ScoreSum      ScoreMode = "sum"
+	ScoreAvg      ScoreMode = "avg"
+	ScoreFirst    ScoreMode = "first"
+	ScoreMax      ScoreMode = "max"
+	ScoreMin      ScoreMode = "min"
+)
+
+type BoostMode string
+
+const (
+	BoostMultiply BoostMode = "multiply"
+	BoostReplace  BoostMode = "replace"
+	BoostSum      BoostMode = "sum"
+	BoostAvg      BoostMode = "avg"
+	BoostMax      BoostMode = "max"
+	BoostMin      BoostMode = "min"
+)
+
+type Modifier string
+
+const (
+	None       Modifier = "none"
+	Log        Modifier = "log"
+	Log1p      Modifier = "log1p"
+	Log2p      Modifier = "log2p"
+	Ln         Modifier = "ln"
+	Ln1p       Modifier = "ln1p"
+	Ln2p       Modifier = "ln2p"
+	Square     Modifier = "square"
+	Sqrt       Modifier = "sqrt"
+	Reciprocal Modifier = "reciprocal"
+)
+
+type FunctionScore struct {
+	Query     esearch.Query   `json:"query,omitempty"`
+	Functions []ScoreFunction `json:"functions,omitempty"`
+	FunctionScoreParam
+}
+
+type FunctionScoreParamFunc func() FunctionScoreParam
+
+type FunctionScoreParam struct {
+	ScoreMode ScoreMode `json:"score_mode,omitempty"`
+	BoostMode BoostMode `json:"boost_mode,omitempty"`
+	MaxBoost  float64   `json:"max_boost,omitempty"`
+	MinScore  float64   `json:"min_score,omitempty"`
+	Boost     float64   `json:"boost,omitempty"`
+}
+
+func (f *FunctionScore) QueryBuild() string {
+	return ""
+}
+
+func (f *FunctionScore) BoolBuild() string {
+	return ""
+}
+
+// ScoreFunction function_score 中的评分函数, Filter 不为空时, 只对满足条件的文档生效
+type ScoreFunction struct {
+	Filter           esearch.Query        `json:"filter,omitempty"`
+	Weight           float64              `json:"weight,omitempty"`
+	FieldValueFactor *FieldValueFactor    `json:"field_value_factor,omitempty"`
+	Gauss            *Decay               `json:"gauss,omitempty"`
+	Linear           *Decay               `json:"linear,omitempty"`
+	Exp              *Decay               `json:"exp,omitempty"`
+	RandomScore      *RandomScore         `json:"random_score,omitempty"`
+	ScriptScore      *ScriptScoreFunction `json:"script_score,omitempty"`
+}
+
+type FieldValueFactor struct {
+	Field    string   `json:"field"`
+	Factor   float64  `json:"factor,omitempty"`
+	Modifier Modifier `json:"modifier,omitempty"`
+	Missing  float64  `json:"missing,omitempty"`
+}
+
+// Decay gauss, linear, exp 衰减函数, 序列化后为 {"field": {"origin": ..., "scale": ...}, "multi_value_mode": ...}
+type Decay struct {
+	Field string
+	DecayParam
+	MultiValueMode string
+}
+
+type DecayParam struct {
+	Origin any     `json:"origin,omitempty"`
+	Scale  any     `json:"scale"`
+	Offset any     `json:"offset,omitempty"`
+	Decay  float64 `json:"decay,omitempty"`
+}
+
+func (d Decay) MarshalJSON() ([]byte, error) {
+	if d.Field == "" {
+		return nil, errors.New("decay function field is empty")
+	}
+
+	decay := make(map[string]any)
+	decay[d.Field] = d.DecayParam
+	if d.MultiValueMode != "" {
+		decay["multi_value_mode"] = d.MultiValueMode
+	}
+
+	return json.Marshal(decay)
+}
+
+type RandomScore struct {
+	Seed  any    `json:"seed,omitempty"`
+	Field string `json:"field,omitempty"`
+}
+
+type ScriptScoreFunction struct {
+	Script esearch.Script `json:"script"`
+}
+
+// ScriptScore script_score 查询, 使用脚本计算文档得分
+type ScriptScore struct {
+	Query  esearch.Query  `json:"query"`
+	Script esearch.Script `json:"script"`
+	ScriptScoreParam
+}
+
+type ScriptScoreParamFunc func() ScriptScoreParam
+
+type ScriptScoreParam struct {
+	MinScore float64 `json:"min_score,omitempty"`
+	Boost    float64 `json:"boost,omitempty"`
+}
+
+func (s *ScriptScore) QueryBuild() string {
+	return ""
+}
+
+func (s *ScriptScore) BoolBuild() string {
+	return ""
+}
+
+// CosineSimilarity 余弦相似度脚本, 用于不支持 knn 的集群, 配合 ScriptScore 使用, 得分加 1 避免出现负数
+func CosineSimilarity(field string, vector []float32) esearch.Script {
+	return vectorScript("cosineSimilarity(params.query_vector, "+quoteField(field)+") + 1.0", vector)
+}
+
+// DotProduct 点积脚本, 用于不支持 knn 的集群, 配合 ScriptScore 使用, 使用 sigmoid 函数避免出现负数
+func DotProduct(field string, vector []float32) esearch.Script {
+	return vectorScript("double value = dotProduct(params.query_vector, "+quoteField(field)+"); return sigmoid(1, Math.E, -value);", vector)
+}
+
+// L2Norm 欧式距离脚本, 用于不支持 knn 的集群, 配合 ScriptScore 使用, 距离越近得分越高
+func L2Norm(field string, vector []float32) esearch.Script {
+	return vectorScript("1 / (1 + l2norm(params.query_vector, "+quoteField(field)+"))", vector)
+}
+
+func vectorScript(source string, vector []float32) esearch.Script {
+	return esearch.Script{
+		Source: source,
+		Params: map[string]any{"query_vector": vector},
+	}
+}
+
+func quoteField(field string) string {
+	return "'" + strings.ReplaceAll(field, "'", "\\'") + "'"
+}
+
+// DisMax dis_max 查询, 文档得分取匹配子查询中的最高得分, 其他子查询得分乘以 tie_breaker 后累加
+type DisMax struct {
+	Queries    []esearch.Query `json:"queries"`
+	TieBreaker float64         `json:"tie_breaker,omitempty"`
+}
+
+func (d *DisMax) QueryBuild() string {
+	return ""
+}
+
+func (d *DisMax) BoolBuild() string {
+	return ""
+}
+
+// Boosting boosting 查询, 匹配 negative 的文档得分乘以 negative_boost
+type Boosting struct {
+	Positive      esearch.Query `json:"positive"`
+	Negative      esearch.Query `json:"negative"`
+	NegativeBoost float64       `json:"negative_boost"`
+}
+
+func (boosting *Boosting) QueryBuild() string {
+	return ""
+}
+
+func (boosting *Boosting) BoolBuild() string {
+	return ""
+}
+
+// ConstantScore constant_score 查询, 匹配 filter 的文档得分均为 boost
+type ConstantScore struct {
+	Filter esearch.Query `json:"filter"`
+	Boost  float64       `json:"boost,omitempty"`
+}
+
+func (c *ConstantScore) QueryBuild() string {
+	return ""
+}
+
+func (c *ConstantScore) BoolBuild() string {
+	return ""
+}
@@ -0,0 +1,105 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/compound"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+func TestScoreQuery(t *testing.T) {
+	extra := func(b *Builder) {
+		b.Where("b", 2)
+	}
+
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "function_score merges fn into must",
+			b:    NewBuilder().Where("a", 1).FunctionScore(extra, compound.ScoreFunction{Weight: 2}),
+			want: `{"query":{"function_score":{"query":{"bool":{"must":[{"term":{"a":1}},{"bool":{"must":[{"term":{"b":2}}]}}]}},"functions":[{"weight":2}]}}}`,
+		},
+		{
+			name: "function_score decay and params",
+			b: NewBuilder().FunctionScore(nil, compound.ScoreFunction{
+				Gauss: &compound.Decay{Field: "date", DecayParam: compound.DecayParam{Origin: "now", Scale: "10d"}},
+			}).FunctionScoreParams(func() compound.FunctionScoreParam {
+				return compound.FunctionScoreParam{ScoreMode: "sum", MaxBoost: 10}
+			}),
+			want: `{"query":{"function_score":{"query":{"match_all":{}},"functions":[{"gauss":{"date":{"origin":"now","scale":"10d"}}}],"score_mode":"sum","max_boost":10}}}`,
+		},
+		{
+			name: "repeated function_score replaces fn and functions, keeps params",
+			b: NewBuilder().FunctionScoreParams(func() compound.FunctionScoreParam {
+				return compound.FunctionScoreParam{BoostMode: "multiply"}
+			}).FunctionScore(extra, compound.ScoreFunction{Weight: 2}).FunctionScore(nil, compound.ScoreFunction{Weight: 3}),
+			want: `{"query":{"function_score":{"query":{"match_all":{}},"functions":[{"weight":3}],"boost_mode":"multiply"}}}`,
+		},
+		{
+			name: "script_score",
+			b:    NewBuilder().Where("a", 1).ScriptScore(nil, esearch.Script{Source: "_score * 2"}, nil),
+			want: `{"query":{"script_score":{"query":{"bool":{"must":[{"term":{"a":1}}]}},"script":{"source":"_score * 2"}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestFunctionScoreDecayWithoutField(t *testing.T) {
+	b := NewBuilder().FunctionScore(nil, compound.ScoreFunction{
+		Linear: &compound.Decay{DecayParam: compound.DecayParam{Scale: "10d"}},
+	})
+
+	if _, err := b.Marshal(); err == nil {
+		t.Errorf("Marshal() expected error for decay function without field")
+	}
+}
+
+func TestCompoundQuery(t *testing.T) {
+	first := func(b *Builder) {
+		b.Where("a", 1)
+	}
+	second := func(b *Builder) {
+		b.Where("b", 1)
+	}
+
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "dis_max",
+			b:    NewBuilder().DisMax(0.3, first, second),
+			want: `{"query":{"dis_max":{"queries":[{"bool":{"must":[{"term":{"a":1}}]}},{"bool":{"must":[{"term":{"b":1}}]}}],"tie_breaker":0.3}}}`,
+		},
+		{
+			name: "boosting",
+			b:    NewBuilder().Boosting(first, second, 0.5),
+			want: `{"query":{"boosting":{"positive":{"bool":{"must":[{"term":{"a":1}}]}},"negative":{"bool":{"must":[{"term":{"b":1}}]}},"negative_boost":0.5}}}`,
+		},
+		{
+			name: "constant_score in filter",
+			b:    NewBuilder().FilterConstantScore(first, 1.2),
+			want: `{"query":{"bool":{"filter":[{"constant_score":{"filter":{"bool":{"must":[{"term":{"a":1}}]}},"boost":1.2}}]}}}`,
+		},
+		{
+			name: "constant_score with other conditions",
+			b:    NewBuilder().Where("x", 1).ConstantScore(first, 1.2),
+			want: `{"query":{"bool":{"must":[{"term":{"x":1}},{"constant_score":{"filter":{"bool":{"must":[{"term":{"a":1}}]}},"boost":1.2}}]}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
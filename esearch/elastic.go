@@ -55,11 +55,34 @@ const (
 
 const (
 	Terms         = "_terms"
+	RareTerms     = "_rareTerms"
+	MultiTerms    = "_multiTerms"
 	Histogram     = "_histogram"
 	Range         = "_range"
 	DateRange     = "_dateRange"
 	DateHistogram = "_dateHistogram"
-	AggsFilter    = "_filter"
+
+	AutoDateHistogram      = "_autoDateHistogram"
+	VariableWidthHistogram = "_variableWidthHistogram"
+	AggsFilter             = "_filter"
+	Nested                 = "_nested"
+	ReverseNested          = "_reverseNested"
+	Sampler                = "_sampler"
+
+	DiversifiedSampler = "_diversifiedSampler"
+	RandomSampler      = "_randomSampler"
+
+	GeoHashGrid = "_geohashGrid"
+	GeoTileGrid = "_geotileGrid"
+	GeoDistance = "_geoDistance"
+	GeoBounds   = "_geoBounds"
+	GeoCentroid = "_geoCentroid"
+
+	SignificantTerms = "_significantTerms"
+	SignificantText  = "_significantText"
+
+	Filters         = "_filters"
+	AdjacencyMatrix = "_adjacencyMatrix"
 
 	Avg           = "_avg"
 	Max           = "_max"
@@ -70,6 +93,26 @@ const (
 	ExtendedStats = "_extendedStats"
 	TopHits       = "_topHits"
 	Cardinality   = "_cardinality"
+
+	Percentiles             = "_percentiles"
+	PercentileRanks         = "_percentileRanks"
+	MedianAbsoluteDeviation = "_medianAbsoluteDeviation"
+
+	Composite = "_composite"
+
+	Derivative        = "_derivative"
+	CumulativeSum     = "_cumulativeSum"
+	MovingFn          = "_movingFn"
+	SerialDiff        = "_serialDiff"
+	AvgBucket         = "_avgBucket"
+	SumBucket         = "_sumBucket"
+	MaxBucket         = "_maxBucket"
+	MinBucket         = "_minBucket"
+	StatsBucket       = "_statsBucket"
+	PercentilesBucket = "_percentilesBucket"
+	BucketScript      = "_bucketScript"
+	BucketSelector    = "_bucketSelector"
+	BucketSort        = "_bucketSort"
 )
 
 type QueryBuilder interface {
@@ -89,6 +132,34 @@ type Order struct {
 
 type SortMap map[string]OrderType
 
+// Script 脚本, Source 为内联脚本, Id 为存储脚本的ID, 二者选其一. 用于脚本查询, 脚本排序, 脚本评分和聚合脚本
+type Script struct {
+	Source string         `json:"source,omitempty"`
+	Id     string         `json:"id,omitempty"`
+	Lang   string         `json:"lang,omitempty"`
+	Params map[string]any `json:"params,omitempty"`
+}
+
+type ScriptSortType string
+
+const (
+	ScriptSortNumber  ScriptSortType = "number"
+	ScriptSortString  ScriptSortType = "string"
+	ScriptSortVersion ScriptSortType = "version"
+)
+
+// ScriptSort 使用脚本计算的值排序
+type ScriptSort struct {
+	Script ScriptSortParam `json:"_script"`
+}
+
+type ScriptSortParam struct {
+	Type   ScriptSortType `json:"type"`
+	Script Script         `json:"script"`
+	Order  OrderType      `json:"order,omitempty"`
+	Mode   string         `json:"mode,omitempty"`
+}
+
 type Sorter interface {
 	Sort()
 }
@@ -101,6 +172,10 @@ func (s SortMap) Sort() {
 
 }
 
+func (s ScriptSort) Sort() {
+
+}
+
 type Paginator interface {
 	Page() uint
 }
@@ -120,10 +195,29 @@ type ElasticQuery struct {
 	PostFilter Query                 `json:"post_filter,omitempty"`
 	Aggs       map[string]Aggregator `json:"aggs,omitempty"`
 	Collapse   Collapsor             `json:"collapse,omitempty"`
+	Knn        []Knn                 `json:"knn,omitempty"`
+	Retriever  Retriever             `json:"retriever,omitempty"`
 }
 
 type Query map[string]QueryBuilder
 
+// Knn 近似 k 近邻搜索, 对 dense_vector 类型的字段进行向量检索
+type Knn struct {
+	Field         string    `json:"field"`
+	QueryVector   []float32 `json:"query_vector"`
+	K             int       `json:"k"`
+	NumCandidates int       `json:"num_candidates"`
+	Filter        Query     `json:"filter,omitempty"`
+	KnnParam
+}
+
+type KnnParamFunc func() KnnParam
+
+type KnnParam struct {
+	Similarity float64 `json:"similarity,omitempty"`
+	Boost      float64 `json:"boost,omitempty"`
+}
+
 type BoolQuery struct {
 	Must               []BoolBuilder `json:"must,omitempty"`
 	MustNot            []BoolBuilder `json:"must_not,omitempty"`
@@ -157,6 +251,10 @@ type Request interface {
 	ScrollQuery() ([]byte, error)
 }
 
+type Retriever interface {
+	Retrieve()
+}
+
 type Collapsor interface {
 	Collapse()
 }
@@ -170,7 +268,8 @@ type CountResult struct {
 }
 
 type ArithmeticResult struct {
-	Value float64 `json:"value"`
+	Value float64  `json:"value"`
+	Keys  []string `json:"keys,omitempty"` // max_bucket, min_bucket 使用, 取得最大值或最小值的桶的 key
 }
 
 type StatsResult struct {
@@ -194,6 +293,17 @@ type StdDeviationBounds struct {
 	Lower float64 `json:"lower"`
 }
 
+// PercentilesResult percentiles, percentile_ranks 聚合结果, 按照请求中 percents, values 的顺序排列
+type PercentilesResult struct {
+	Values []PercentileValue `json:"values"`
+}
+
+type PercentileValue struct {
+	Key           float64 `json:"key"`
+	Value         float64 `json:"value"`
+	ValueAsString string  `json:"value_as_string,omitempty"`
+}
+
 type TermsResult struct {
 	DocCountErrorUpperBound int      `json:"doc_count_error_upper_bound"`
 	SumOtherDocCount        int      `json:"sum_other_doc_count"`
@@ -201,17 +311,80 @@ type TermsResult struct {
 }
 
 type Bucket struct {
-	Key         string `json:"key"`
-	DocCount    int    `json:"doc_count"`
-	KeyAsString string `json:"key_as_string,omitempty"` // histogram, date_histogram 使用
+	Key          string         `json:"key"` // 数字 key 为原始文本, 例如 histogram 的 "1704067200000"
+	DocCount     int            `json:"doc_count"`
+	KeyAsString  string         `json:"key_as_string,omitempty"` // histogram, date_histogram 使用
+	CompositeKey map[string]any `json:"composite_key,omitempty"` // composite 使用, 键为数据源名称, 数字为 json.Number
+	Keys         []any          `json:"keys,omitempty"`          // multi_terms 使用, 与请求中字段的顺序相同, 数字为 json.Number
+	Min          float64        `json:"min,omitempty"`           // variable_width_histogram 使用, 桶中的最小值
+	Max          float64        `json:"max,omitempty"`           // variable_width_histogram 使用, 桶中的最大值
 	RangeBucket
 	Aggs AggsResult `json:"aggs,omitempty"`
 }
 
-type HistogramResult struct {
+// CompositeResult AfterKey 为空时表示已经没有下一页, 其中的数字为 json.Number, 可以原样设置为下一页的 after
+type CompositeResult struct {
+	AfterKey map[string]any `json:"after_key,omitempty"`
+	Buckets  []Bucket       `json:"buckets"`
+}
+
+// FiltersResult filters, adjacency_matrix 聚合结果, Bucket.Key 为过滤条件的名称
+type FiltersResult struct {
 	Buckets []Bucket `json:"buckets"`
 }
 
+// SignificantResult significant_terms, significant_text 聚合结果, DocCount 为前景集合的文档数量, BgCount 为背景集合的文档数量
+type SignificantResult struct {
+	DocCount int                 `json:"doc_count"`
+	BgCount  int                 `json:"bg_count"`
+	Buckets  []SignificantBucket `json:"buckets"`
+}
+
+type SignificantBucket struct {
+	Bucket
+	Score   float64 `json:"score"`
+	BgCount int     `json:"bg_count"`
+}
+
+// GeoGridResult geohash_grid, geotile_grid 聚合结果, Bucket.Key 为 geohash 或者 zoom/x/y 格式的 geotile
+type GeoGridResult struct {
+	Buckets []GeoGridBucket `json:"buckets"`
+}
+
+// GeoGridBucket Tile 只在 geotile_grid 聚合中有值
+type GeoGridBucket struct {
+	Bucket
+	Tile *GeoTile `json:"tile,omitempty"`
+}
+
+type GeoTile struct {
+	Zoom int `json:"zoom"`
+	X    int `json:"x"`
+	Y    int `json:"y"`
+}
+
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// GeoBoundsResult 没有文档时 TopLeft, BottomRight 为空
+type GeoBoundsResult struct {
+	TopLeft     *GeoPoint `json:"top_left"`
+	BottomRight *GeoPoint `json:"bottom_right"`
+}
+
+type GeoCentroidResult struct {
+	Location *GeoPoint `json:"location"`
+	Count    int       `json:"count"`
+}
+
+// HistogramResult Interval 只在 auto_date_histogram 聚合中有值, 为 es 选择的时间间隔, 例如 1d
+type HistogramResult struct {
+	Buckets  []Bucket `json:"buckets"`
+	Interval string   `json:"interval,omitempty"`
+}
+
 type RangeResult struct {
 	Buckets []Bucket
 }
@@ -248,4 +421,14 @@ type AggsResult struct {
 	Stats         map[string]*StatsResult
 	ExtendedStats map[string]*ExtendStatsResult
 	TopHits       *HitsResult
+	Percentiles   map[string]*PercentilesResult
+	Composite     map[string]*CompositeResult
+	SingleBucket  map[string]*Bucket // filter, nested, reverse_nested, sampler 等单桶聚合, 子聚合结果在 Bucket.Aggs 中
+	Filters       map[string]*FiltersResult
+	Significant   map[string]*SignificantResult
+	GeoGrid       map[string]*GeoGridResult
+	GeoBounds     map[string]*GeoBoundsResult
+	GeoCentroid   map[string]*GeoCentroidResult
+	// MedianAbsoluteDeviation median_absolute_deviation 聚合结果, 衡量数据的离散程度, 与 avg, max 等计算结果区分开
+	MedianAbsoluteDeviation map[string]*ArithmeticResult
 }
@@ -1,19 +1,28 @@
 package fulltext
 
+import (
+	"strconv"
+	"strings"
+)
+
 type MultiMatcher interface {
 	MultiMatch() string
 }
 
 type TextQuery struct {
-	Match             map[string]MatchQuery `json:"match,omitempty"`
-	MatchPhrase       map[string]MatchQuery `json:"match_phrase,omitempty"`
-	MatchPhrasePrefix map[string]MatchQuery `json:"match_phrase_fix,omitempty"`
-	MultiMatch        *MultiMatchQuery      `json:"multi_match,omitempty"`
+	Match             map[string]MatchQuery   `json:"match,omitempty"`
+	MatchPhrase       map[string]MatchQuery   `json:"match_phrase,omitempty"`
+	MatchPhrasePrefix map[string]MatchQuery   `json:"match_phrase_prefix,omitempty"`
+	MultiMatch        *MultiMatchQuery        `json:"multi_match,omitempty"`
+	QueryString       *QueryStringQuery       `json:"query_string,omitempty"`
+	SimpleQueryString *SimpleQueryStringQuery `json:"simple_query_string,omitempty"`
+	MoreLikeThis      *MoreLikeThisQuery      `json:"more_like_this,omitempty"`
 }
 
 type MatchQuery struct {
 	Query string `json:"query,omitempty"`
 	AppendParams
+	Slop int `json:"slop,omitempty"` // 只在 match_phrase, match_phrase_prefix 中使用
 }
 
 type MultiMatchQuery struct {
@@ -36,6 +45,185 @@ type AppendParams struct {
 	MinimumShouldMatch string  `json:"minimum_should_match,omitempty"`
 }
 
+type PhraseParamsFunc func() PhraseParams
+
+// PhraseParams match_phrase, match_phrase_prefix 的参数, Slop 为词之间允许间隔的位置数
+type PhraseParams struct {
+	AppendParams
+	Slop int `json:"slop,omitempty"`
+}
+
+type Operator string
+
+const (
+	Or  Operator = "OR"
+	And Operator = "AND"
+)
+
+type QueryStringQuery struct {
+	Query string `json:"query"`
+	QueryStringParam
+}
+
+type QueryStringParamFunc func() QueryStringParam
+
+type QueryStringParam struct {
+	DefaultField         string   `json:"default_field,omitempty"`
+	Fields               []string `json:"fields,omitempty"`
+	DefaultOperator      Operator `json:"default_operator,omitempty"`
+	Analyzer             string   `json:"analyzer,omitempty"`
+	QuoteAnalyzer        string   `json:"quote_analyzer,omitempty"`
+	AnalyzeWildcard      bool     `json:"analyze_wildcard,omitempty"`
+	AllowLeadingWildcard *bool    `json:"allow_leading_wildcard,omitempty"` // es 默认为 true, 需要禁用时设置为 false
+	Fuzziness            string   `json:"fuzziness,omitempty"`
+	PhraseSlop           int      `json:"phrase_slop,omitempty"`
+	Lenient              bool     `json:"lenient,omitempty"`
+	MinimumShouldMatch   string   `json:"minimum_should_match,omitempty"`
+	TimeZone             string   `json:"time_zone,omitempty"`
+	Boost                float32  `json:"boost,omitempty"`
+}
+
+type Flag string
+
+const (
+	FlagAll        Flag = "ALL"
+	FlagNone       Flag = "NONE"
+	FlagAnd        Flag = "AND"
+	FlagOr         Flag = "OR"
+	FlagNot        Flag = "NOT"
+	FlagPrefix     Flag = "PREFIX"
+	FlagPhrase     Flag = "PHRASE"
+	FlagPrecedence Flag = "PRECEDENCE"
+	FlagEscape     Flag = "ESCAPE"
+	FlagWhitespace Flag = "WHITESPACE"
+	FlagFuzzy      Flag = "FUZZY"
+	FlagNear       Flag = "NEAR"
+	FlagSlop       Flag = "SLOP"
+)
+
+// Flags 组合 simple_query_string 中允许使用的操作符, 例如: OR|AND|PREFIX
+func Flags(flags ...Flag) string {
+	set := make([]string, len(flags))
+	for i, flag := range flags {
+		set[i] = string(flag)
+	}
+
+	return strings.Join(set, "|")
+}
+
+type SimpleQueryStringQuery struct {
+	Query string `json:"query"`
+	SimpleQueryStringParam
+}
+
+type SimpleQueryStringParamFunc func() SimpleQueryStringParam
+
+type SimpleQueryStringParam struct {
+	Fields             []string `json:"fields,omitempty"`
+	DefaultOperator    Operator `json:"default_operator,omitempty"`
+	Analyzer           string   `json:"analyzer,omitempty"`
+	AnalyzeWildcard    bool     `json:"analyze_wildcard,omitempty"`
+	Flags              string   `json:"flags,omitempty"`
+	FuzzyPrefixLength  int      `json:"fuzzy_prefix_length,omitempty"`
+	FuzzyMaxExpansions int      `json:"fuzzy_max_expansions,omitempty"`
+	Lenient            bool     `json:"lenient,omitempty"`
+	MinimumShouldMatch string   `json:"minimum_should_match,omitempty"`
+	QuoteFieldSuffix   string   `json:"quote_field_suffix,omitempty"`
+	Boost              float32  `json:"boost,omitempty"`
+}
+
+// FieldBoost 设置字段的权重, 例如: FieldBoost("title", 3) 返回 title^3
+func FieldBoost(field string, boost float64) string {
+	return field + "^" + strconv.FormatFloat(boost, 'f', -1, 64)
+}
+
+// EscapeQueryString 转义 query_string 中的保留字符, 使用户输入按照字面值匹配. < 和 > 无法转义, 直接删除
+func EscapeQueryString(value string) string {
+	var builder strings.Builder
+	builder.Grow(len(value))
+
+	for _, char := range value {
+		switch char {
+		case '<', '>':
+			continue
+		case '+', '-', '=', '&', '|', '!', '(', ')', '{', '}', '[', ']', '^', '"', '~', '*', '?', ':', '\\', '/':
+			builder.WriteRune('\\')
+		}
+		builder.WriteRune(char)
+	}
+
+	return builder.String()
+}
+
+// EscapeSimpleQueryString 转义 simple_query_string 中的操作符, 使用户输入按照字面值匹配
+func EscapeSimpleQueryString(value string) string {
+	var builder strings.Builder
+	builder.Grow(len(value))
+
+	for _, char := range value {
+		switch char {
+		case '+', '|', '-', '"', '*', '(', ')', '~', '\\':
+			builder.WriteRune('\\')
+		}
+		builder.WriteRune(char)
+	}
+
+	return builder.String()
+}
+
+type MoreLikeThisQuery struct {
+	Fields []string `json:"fields,omitempty"`
+	LikeSpec
+	MoreLikeThisParam
+}
+
+// LikeSpec more_like_this 的 like 和 unlike, 支持文本 LikeText 和文档 LikeDocument
+type LikeSpec struct {
+	Like   []Liker `json:"like"`
+	Unlike []Liker `json:"unlike,omitempty"`
+}
+
+type Liker interface {
+	Like()
+}
+
+type LikeText string
+
+func (text LikeText) Like() {
+
+}
+
+// LikeDocument Index 和 Id 引用已存在的文档, Index 和 Doc 为人工构造的文档
+type LikeDocument struct {
+	Index   string         `json:"_index,omitempty"`
+	Id      string         `json:"_id,omitempty"`
+	Doc     map[string]any `json:"doc,omitempty"`
+	Fields  []string       `json:"fields,omitempty"`
+	Routing string         `json:"routing,omitempty"`
+}
+
+func (doc LikeDocument) Like() {
+
+}
+
+type MoreLikeThisParamFunc func() MoreLikeThisParam
+
+type MoreLikeThisParam struct {
+	MinTermFreq        int      `json:"min_term_freq,omitempty"`
+	MaxQueryTerms      int      `json:"max_query_terms,omitempty"`
+	MinDocFreq         int      `json:"min_doc_freq,omitempty"`
+	MaxDocFreq         int      `json:"max_doc_freq,omitempty"`
+	MinWordLength      int      `json:"min_word_length,omitempty"`
+	MaxWordLength      int      `json:"max_word_length,omitempty"`
+	StopWords          []string `json:"stop_words,omitempty"`
+	Analyzer           string   `json:"analyzer,omitempty"`
+	MinimumShouldMatch string   `json:"minimum_should_match,omitempty"`
+	FailOnUnsupported  *bool    `json:"fail_on_unsupported_field,omitempty"`
+	BoostTerms         float32  `json:"boost_terms,omitempty"`
+	Include            bool     `json:"include,omitempty"`
+	Boost              float32  `json:"boost,omitempty"`
+}
+
 func (m MultiMatchQuery) MultiMatch() string {
 	return ""
 }
@@ -0,0 +1,47 @@
+package fulltext
+
+import "testing"
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name   string
+		escape func(string) string
+		value  string
+		want   string
+	}{
+		{name: "query_string reserved", escape: EscapeQueryString, value: `(1+1):2`, want: `\(1\+1\)\:2`},
+		{name: "query_string removes angle brackets", escape: EscapeQueryString, value: `a<b>c`, want: `abc`},
+		{name: "query_string path", escape: EscapeQueryString, value: `a/b\c`, want: `a\/b\\c`},
+		{name: "query_string plain", escape: EscapeQueryString, value: `中国 电信`, want: `中国 电信`},
+		{name: "simple_query_string operators", escape: EscapeSimpleQueryString, value: `-foo | "bar"*`, want: `\-foo \| \"bar\"\*`},
+		{name: "simple_query_string keeps colon", escape: EscapeSimpleQueryString, value: `a:b`, want: `a:b`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.escape(tt.value); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldBoostAndFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "integer boost", got: FieldBoost("title", 3), want: "title^3"},
+		{name: "decimal boost", got: FieldBoost("title", 1.5), want: "title^1.5"},
+		{name: "flags", got: Flags(FlagOr, FlagPrefix), want: "OR|PREFIX"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+}
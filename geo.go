@@ -0,0 +1,273 @@
+package elastic
+
+import (
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"github.com/KingSolvewer/elasticsearch-query-builder/geo"
+)
+
+// WhereGeoDistance Must geo_distance 查询, 距离某个点一定范围内的文档
+func WhereGeoDistance(field string, point geo.Point, distance string, paramFn geo.GeoDistanceParamFunc) *Builder {
+	return builder.WhereGeoDistance(field, point, distance, paramFn)
+}
+
+// WhereGeoDistance Must geo_distance 查询, 距离某个点一定范围内的文档
+func (b *Builder) WhereGeoDistance(field string, point geo.Point, distance string, paramFn geo.GeoDistanceParamFunc) *Builder {
+	b.geoDistance(esearch.Must, field, point, distance, paramFn)
+
+	return b
+}
+
+// WhereNotGeoDistance MustNot geo_distance 查询
+func WhereNotGeoDistance(field string, point geo.Point, distance string, paramFn geo.GeoDistanceParamFunc) *Builder {
+	return builder.WhereNotGeoDistance(field, point, distance, paramFn)
+}
+
+// WhereNotGeoDistance MustNot geo_distance 查询
+func (b *Builder) WhereNotGeoDistance(field string, point geo.Point, distance string, paramFn geo.GeoDistanceParamFunc) *Builder {
+	b.geoDistance(esearch.MustNot, field, point, distance, paramFn)
+
+	return b
+}
+
+// OrWhereGeoDistance Should geo_distance 查询
+func OrWhereGeoDistance(field string, point geo.Point, distance string, paramFn geo.GeoDistanceParamFunc) *Builder {
+	return builder.OrWhereGeoDistance(field, point, distance, paramFn)
+}
+
+// OrWhereGeoDistance Should geo_distance 查询
+func (b *Builder) OrWhereGeoDistance(field string, point geo.Point, distance string, paramFn geo.GeoDistanceParamFunc) *Builder {
+	b.geoDistance(esearch.Should, field, point, distance, paramFn)
+
+	return b
+}
+
+// FilterGeoDistance Filter geo_distance 查询
+func FilterGeoDistance(field string, point geo.Point, distance string, paramFn geo.GeoDistanceParamFunc) *Builder {
+	return builder.FilterGeoDistance(field, point, distance, paramFn)
+}
+
+// FilterGeoDistance Filter geo_distance 查询
+func (b *Builder) FilterGeoDistance(field string, point geo.Point, distance string, paramFn geo.GeoDistanceParamFunc) *Builder {
+	b.geoDistance(esearch.FilterClause, field, point, distance, paramFn)
+
+	return b
+}
+
+// WhereGeoBoundingBox Must geo_bounding_box 查询, 落在矩形范围内的文档, topLeft 为 geo.WKT 格式的 BBOX 时 bottomRight 传 nil
+func WhereGeoBoundingBox(field string, topLeft, bottomRight geo.Point, paramFn geo.BoundingBoxParamFunc) *Builder {
+	return builder.WhereGeoBoundingBox(field, topLeft, bottomRight, paramFn)
+}
+
+// WhereGeoBoundingBox Must geo_bounding_box 查询, 落在矩形范围内的文档, topLeft 为 geo.WKT 格式的 BBOX 时 bottomRight 传 nil
+func (b *Builder) WhereGeoBoundingBox(field string, topLeft, bottomRight geo.Point, paramFn geo.BoundingBoxParamFunc) *Builder {
+	b.geoBoundingBox(esearch.Must, field, topLeft, bottomRight, paramFn)
+
+	return b
+}
+
+// WhereNotGeoBoundingBox MustNot geo_bounding_box 查询
+func WhereNotGeoBoundingBox(field string, topLeft, bottomRight geo.Point, paramFn geo.BoundingBoxParamFunc) *Builder {
+	return builder.WhereNotGeoBoundingBox(field, topLeft, bottomRight, paramFn)
+}
+
+// WhereNotGeoBoundingBox MustNot geo_bounding_box 查询
+func (b *Builder) WhereNotGeoBoundingBox(field string, topLeft, bottomRight geo.Point, paramFn geo.BoundingBoxParamFunc) *Builder {
+	b.geoBoundingBox(esearch.MustNot, field, topLeft, bottomRight, paramFn)
+
+	return b
+}
+
+// OrWhereGeoBoundingBox Should geo_bounding_box 查询
+func OrWhereGeoBoundingBox(field string, topLeft, bottomRight geo.Point, paramFn geo.BoundingBoxParamFunc) *Builder {
+	return builder.OrWhereGeoBoundingBox(field, topLeft, bottomRight, paramFn)
+}
+
+// OrWhereGeoBoundingBox Should geo_bounding_box 查询
+func (b *Builder) OrWhereGeoBoundingBox(field string, topLeft, bottomRight geo.Point, paramFn geo.BoundingBoxParamFunc) *Builder {
+	b.geoBoundingBox(esearch.Should, field, topLeft, bottomRight, paramFn)
+
+	return b
+}
+
+// FilterGeoBoundingBox Filter geo_bounding_box 查询
+func FilterGeoBoundingBox(field string, topLeft, bottomRight geo.Point, paramFn geo.BoundingBoxParamFunc) *Builder {
+	return builder.FilterGeoBoundingBox(field, topLeft, bottomRight, paramFn)
+}
+
+// FilterGeoBoundingBox Filter geo_bounding_box 查询
+func (b *Builder) FilterGeoBoundingBox(field string, topLeft, bottomRight geo.Point, paramFn geo.BoundingBoxParamFunc) *Builder {
+	b.geoBoundingBox(esearch.FilterClause, field, topLeft, bottomRight, paramFn)
+
+	return b
+}
+
+// WhereGeoPolygon Must geo_polygon 查询, 落在多边形范围内的文档
+func WhereGeoPolygon(field string, points []geo.Point, paramFn geo.PolygonParamFunc) *Builder {
+	return builder.WhereGeoPolygon(field, points, paramFn)
+}
+
+// WhereGeoPolygon Must geo_polygon 查询, 落在多边形范围内的文档
+func (b *Builder) WhereGeoPolygon(field string, points []geo.Point, paramFn geo.PolygonParamFunc) *Builder {
+	b.geoPolygon(esearch.Must, field, points, paramFn)
+
+	return b
+}
+
+// WhereNotGeoPolygon MustNot geo_polygon 查询
+func WhereNotGeoPolygon(field string, points []geo.Point, paramFn geo.PolygonParamFunc) *Builder {
+	return builder.WhereNotGeoPolygon(field, points, paramFn)
+}
+
+// WhereNotGeoPolygon MustNot geo_polygon 查询
+func (b *Builder) WhereNotGeoPolygon(field string, points []geo.Point, paramFn geo.PolygonParamFunc) *Builder {
+	b.geoPolygon(esearch.MustNot, field, points, paramFn)
+
+	return b
+}
+
+// OrWhereGeoPolygon Should geo_polygon 查询
+func OrWhereGeoPolygon(field string, points []geo.Point, paramFn geo.PolygonParamFunc) *Builder {
+	return builder.OrWhereGeoPolygon(field, points, paramFn)
+}
+
+// OrWhereGeoPolygon Should geo_polygon 查询
+func (b *Builder) OrWhereGeoPolygon(field string, points []geo.Point, paramFn geo.PolygonParamFunc) *Builder {
+	b.geoPolygon(esearch.Should, field, points, paramFn)
+
+	return b
+}
+
+// FilterGeoPolygon Filter geo_polygon 查询
+func FilterGeoPolygon(field string, points []geo.Point, paramFn geo.PolygonParamFunc) *Builder {
+	return builder.FilterGeoPolygon(field, points, paramFn)
+}
+
+// FilterGeoPolygon Filter geo_polygon 查询
+func (b *Builder) FilterGeoPolygon(field string, points []geo.Point, paramFn geo.PolygonParamFunc) *Builder {
+	b.geoPolygon(esearch.FilterClause, field, points, paramFn)
+
+	return b
+}
+
+// WhereGeoShape Must geo_shape 查询, 与指定形状满足 relation 关系的文档
+func WhereGeoShape(field string, shape geo.Shape, relation geo.Relation, paramFn geo.ShapeParamFunc) *Builder {
+	return builder.WhereGeoShape(field, shape, relation, paramFn)
+}
+
+// WhereGeoShape Must geo_shape 查询, 与指定形状满足 relation 关系的文档
+func (b *Builder) WhereGeoShape(field string, shape geo.Shape, relation geo.Relation, paramFn geo.ShapeParamFunc) *Builder {
+	b.geoShape(esearch.Must, field, shape, relation, paramFn)
+
+	return b
+}
+
+// WhereNotGeoShape MustNot geo_shape 查询
+func WhereNotGeoShape(field string, shape geo.Shape, relation geo.Relation, paramFn geo.ShapeParamFunc) *Builder {
+	return builder.WhereNotGeoShape(field, shape, relation, paramFn)
+}
+
+// WhereNotGeoShape MustNot geo_shape 查询
+func (b *Builder) WhereNotGeoShape(field string, shape geo.Shape, relation geo.Relation, paramFn geo.ShapeParamFunc) *Builder {
+	b.geoShape(esearch.MustNot, field, shape, relation, paramFn)
+
+	return b
+}
+
+// OrWhereGeoShape Should geo_shape 查询
+func OrWhereGeoShape(field string, shape geo.Shape, relation geo.Relation, paramFn geo.ShapeParamFunc) *Builder {
+	return builder.OrWhereGeoShape(field, shape, relation, paramFn)
+}
+
+// OrWhereGeoShape Should geo_shape 查询
+func (b *Builder) OrWhereGeoShape(field string, shape geo.Shape, relation geo.Relation, paramFn geo.ShapeParamFunc) *Builder {
+	b.geoShape(esearch.Should, field, shape, relation, paramFn)
+
+	return b
+}
+
+// FilterGeoShape Filter geo_shape 查询
+func FilterGeoShape(field string, shape geo.Shape, relation geo.Relation, paramFn geo.ShapeParamFunc) *Builder {
+	return builder.FilterGeoShape(field, shape, relation, paramFn)
+}
+
+// FilterGeoShape Filter geo_shape 查询
+func (b *Builder) FilterGeoShape(field string, shape geo.Shape, relation geo.Relation, paramFn geo.ShapeParamFunc) *Builder {
+	b.geoShape(esearch.FilterClause, field, shape, relation, paramFn)
+
+	return b
+}
+
+func (b *Builder) geoDistance(clauseTyp esearch.BoolClauseType, field string, point geo.Point, distance string, paramFn geo.GeoDistanceParamFunc) {
+	if point == nil || distance == "" {
+		return
+	}
+
+	geoDistance := geo.GeoDistance{
+		Field:    field,
+		Point:    point,
+		Distance: distance,
+	}
+
+	if paramFn != nil {
+		geoDistance.GeoDistanceParam = paramFn()
+	}
+
+	b.append(clauseTyp, geo.GeoDistanceQuery{GeoDistance: geoDistance})
+}
+
+func (b *Builder) geoBoundingBox(clauseTyp esearch.BoolClauseType, field string, topLeft, bottomRight geo.Point, paramFn geo.BoundingBoxParamFunc) {
+	boundingBox := geo.GeoBoundingBox{
+		Field: field,
+	}
+
+	// topLeft 为 WKT 格式的 BBOX 时, 单独作为矩形范围, 不需要 bottomRight
+	if wkt, ok := topLeft.(geo.WKT); ok && bottomRight == nil {
+		boundingBox.Wkt = wkt
+	} else if topLeft != nil && bottomRight != nil {
+		boundingBox.TopLeft = topLeft
+		boundingBox.BottomRight = bottomRight
+	} else {
+		return
+	}
+
+	if paramFn != nil {
+		boundingBox.BoundingBoxParam = paramFn()
+	}
+
+	b.append(clauseTyp, geo.GeoBoundingBoxQuery{GeoBoundingBox: boundingBox})
+}
+
+func (b *Builder) geoPolygon(clauseTyp esearch.BoolClauseType, field string, points []geo.Point, paramFn geo.PolygonParamFunc) {
+	if len(points) < 3 {
+		return
+	}
+
+	polygon := geo.GeoPolygon{
+		Field:  field,
+		Points: points,
+	}
+
+	if paramFn != nil {
+		polygon.PolygonParam = paramFn()
+	}
+
+	b.append(clauseTyp, geo.GeoPolygonQuery{GeoPolygon: polygon})
+}
+
+func (b *Builder) geoShape(clauseTyp esearch.BoolClauseType, field string, shape geo.Shape, relation geo.Relation, paramFn geo.ShapeParamFunc) {
+	if shape == nil {
+		return
+	}
+
+	geoShape := geo.GeoShape{
+		Field:    field,
+		Shape:    shape,
+		Relation: relation,
+	}
+
+	if paramFn != nil {
+		geoShape.ShapeParam = paramFn()
+	}
+
+	b.append(clauseTyp, geo.GeoShapeQuery{GeoShape: geoShape})
+}
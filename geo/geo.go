@@ -0,0 +1,282 @@
+package geo
+
+import (
+	"encoding/json"
+)
+
+type Relation string
+
+const (
+	Intersects Relation = "intersects"
+	Disjoint   Relation = "disjoint"
+	Within     Relation = "within"
+	Contains   Relation = "contains"
+)
+
+type DistanceType string
+
+const (
+	Arc   DistanceType = "arc"
+	Plane DistanceType = "plane"
+)
+
+type ValidationMethod string
+
+const (
+	Strict          ValidationMethod = "STRICT"
+	IgnoreMalformed ValidationMethod = "IGNORE_MALFORMED"
+	Coerce          ValidationMethod = "COERCE"
+)
+
+const (
+	TypePoint           = "point"
+	TypeLineString      = "linestring"
+	TypePolygon         = "polygon"
+	TypeMultiPoint      = "multipoint"
+	TypeMultiLineString = "multilinestring"
+	TypeMultiPolygon    = "multipolygon"
+	TypeEnvelope        = "envelope"
+)
+
+// Point geo_point 类型的值, 支持 LatLon, GeoHash, WKT
+type Point interface {
+	GeoPoint()
+}
+
+// Shape geo_shape 查询的形状, 支持 GeoJSON, WKT, IndexedShape
+type Shape interface {
+	GeoShape()
+}
+
+type LatLon struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+func (p LatLon) GeoPoint() {
+
+}
+
+// coordinates GeoJSON 中坐标的顺序为 [lon, lat]
+func (p LatLon) coordinates() []float64 {
+	return []float64{p.Lon, p.Lat}
+}
+
+type GeoHash string
+
+func (h GeoHash) GeoPoint() {
+
+}
+
+// WKT Well-Known Text 格式, 例如: POINT (-71.34 41.12), BBOX (-74.1, -71.12, 40.73, 40.01)
+type WKT string
+
+func (w WKT) GeoPoint() {
+
+}
+
+func (w WKT) GeoShape() {
+
+}
+
+type GeoJSON struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+func (g GeoJSON) GeoShape() {
+
+}
+
+// Envelope 矩形, 由左上角和右下角两个点确定
+func Envelope(topLeft, bottomRight LatLon) GeoJSON {
+	return GeoJSON{
+		Type:        TypeEnvelope,
+		Coordinates: [][]float64{topLeft.coordinates(), bottomRight.coordinates()},
+	}
+}
+
+// Polygon 多边形, 首尾的点不相同时, 自动闭合
+func Polygon(points ...LatLon) GeoJSON {
+	ring := make([][]float64, 0, len(points)+1)
+	for _, point := range points {
+		ring = append(ring, point.coordinates())
+	}
+
+	if len(points) > 0 && points[0] != points[len(points)-1] {
+		ring = append(ring, points[0].coordinates())
+	}
+
+	return GeoJSON{
+		Type:        TypePolygon,
+		Coordinates: [][][]float64{ring},
+	}
+}
+
+func PointShape(point LatLon) GeoJSON {
+	return GeoJSON{
+		Type:        TypePoint,
+		Coordinates: point.coordinates(),
+	}
+}
+
+// IndexedShape 使用其他索引中已存储的形状
+type IndexedShape struct {
+	Index   string `json:"index,omitempty"`
+	Id      string `json:"id"`
+	Path    string `json:"path,omitempty"`
+	Routing string `json:"routing,omitempty"`
+}
+
+func (s IndexedShape) GeoShape() {
+
+}
+
+type GeoDistanceQuery struct {
+	GeoDistance GeoDistance `json:"geo_distance"`
+}
+
+type GeoDistance struct {
+	Field    string
+	Point    Point
+	Distance string
+	GeoDistanceParam
+}
+
+type GeoDistanceParamFunc func() GeoDistanceParam
+
+type GeoDistanceParam struct {
+	DistanceType     DistanceType     `json:"distance_type,omitempty"`
+	ValidationMethod ValidationMethod `json:"validation_method,omitempty"`
+	IgnoreUnmapped   bool             `json:"ignore_unmapped,omitempty"`
+}
+
+func (g GeoDistance) MarshalJSON() ([]byte, error) {
+	return fieldMarshal(g.Field, g.Point, struct {
+		Distance string `json:"distance"`
+		GeoDistanceParam
+	}{
+		Distance:         g.Distance,
+		GeoDistanceParam: g.GeoDistanceParam,
+	})
+}
+
+func (g GeoDistanceQuery) BoolBuild() string {
+	return ""
+}
+
+type GeoBoundingBoxQuery struct {
+	GeoBoundingBox GeoBoundingBox `json:"geo_bounding_box"`
+}
+
+type GeoBoundingBox struct {
+	Field string
+	BoundingBox
+	BoundingBoxParam
+}
+
+type BoundingBox struct {
+	TopLeft     Point `json:"top_left,omitempty"`
+	BottomRight Point `json:"bottom_right,omitempty"`
+	Wkt         WKT   `json:"wkt,omitempty"`
+}
+
+type BoundingBoxParamFunc func() BoundingBoxParam
+
+type BoundingBoxParam struct {
+	ValidationMethod ValidationMethod `json:"validation_method,omitempty"`
+	IgnoreUnmapped   bool             `json:"ignore_unmapped,omitempty"`
+}
+
+func (g GeoBoundingBox) MarshalJSON() ([]byte, error) {
+	return fieldMarshal(g.Field, g.BoundingBox, g.BoundingBoxParam)
+}
+
+func (g GeoBoundingBoxQuery) BoolBuild() string {
+	return ""
+}
+
+type GeoPolygonQuery struct {
+	GeoPolygon GeoPolygon `json:"geo_polygon"`
+}
+
+type GeoPolygon struct {
+	Field  string
+	Points []Point
+	PolygonParam
+}
+
+type PolygonParamFunc func() PolygonParam
+
+type PolygonParam struct {
+	ValidationMethod ValidationMethod `json:"validation_method,omitempty"`
+	IgnoreUnmapped   bool             `json:"ignore_unmapped,omitempty"`
+}
+
+func (g GeoPolygon) MarshalJSON() ([]byte, error) {
+	return fieldMarshal(g.Field, struct {
+		Points []Point `json:"points"`
+	}{Points: g.Points}, g.PolygonParam)
+}
+
+func (g GeoPolygonQuery) BoolBuild() string {
+	return ""
+}
+
+type GeoShapeQuery struct {
+	GeoShape GeoShape `json:"geo_shape"`
+}
+
+type GeoShape struct {
+	Field    string
+	Shape    Shape
+	Relation Relation
+	ShapeParam
+}
+
+type ShapeParamFunc func() ShapeParam
+
+type ShapeParam struct {
+	IgnoreUnmapped bool `json:"ignore_unmapped,omitempty"`
+}
+
+func (g GeoShape) MarshalJSON() ([]byte, error) {
+	shape := make(map[string]any)
+	if indexedShape, ok := g.Shape.(IndexedShape); ok {
+		shape["indexed_shape"] = indexedShape
+	} else {
+		shape["shape"] = g.Shape
+	}
+
+	if g.Relation != "" {
+		shape["relation"] = g.Relation
+	}
+
+	return fieldMarshal(g.Field, shape, g.ShapeParam)
+}
+
+func (g GeoShapeQuery) BoolBuild() string {
+	return ""
+}
+
+// fieldMarshal 将字段名作为 key 的值与查询参数合并成同一个 json 对象
+func fieldMarshal(field string, value any, param any) ([]byte, error) {
+	paramBytes, err := json.Marshal(param)
+	if err != nil {
+		return nil, err
+	}
+
+	query := make(map[string]json.RawMessage)
+	err = json.Unmarshal(paramBytes, &query)
+	if err != nil {
+		return nil, err
+	}
+
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	query[field] = valueBytes
+
+	return json.Marshal(query)
+}
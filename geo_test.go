@@ -0,0 +1,61 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/geo"
+)
+
+func TestGeoQuery(t *testing.T) {
+	triangle := []geo.LatLon{{Lat: 1, Lon: 2}, {Lat: 3, Lon: 4}, {Lat: 5, Lon: 6}}
+
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "geo_distance",
+			b: NewBuilder().FilterGeoDistance("location", geo.LatLon{Lat: 40, Lon: -70}, "12km", func() geo.GeoDistanceParam {
+				return geo.GeoDistanceParam{DistanceType: "arc"}
+			}),
+			want: `{"query":{"bool":{"filter":[{"geo_distance":{"distance":"12km","distance_type":"arc","location":{"lat":40,"lon":-70}}}]}}}`,
+		},
+		{
+			name: "geo_bounding_box with two points",
+			b:    NewBuilder().FilterGeoBoundingBox("location", geo.LatLon{Lat: 40.73, Lon: -74.1}, geo.GeoHash("dr5r9"), nil),
+			want: `{"query":{"bool":{"filter":[{"geo_bounding_box":{"location":{"top_left":{"lat":40.73,"lon":-74.1},"bottom_right":"dr5r9"}}}]}}}`,
+		},
+		{
+			name: "geo_bounding_box with standalone wkt",
+			b:    NewBuilder().FilterGeoBoundingBox("location", geo.WKT("BBOX (-74.1, -71.12, 40.73, 40.01)"), nil, nil),
+			want: `{"query":{"bool":{"filter":[{"geo_bounding_box":{"location":{"wkt":"BBOX (-74.1, -71.12, 40.73, 40.01)"}}}]}}}`,
+		},
+		{
+			name: "geo_bounding_box without bottom_right is skipped",
+			b:    NewBuilder().FilterGeoBoundingBox("location", geo.LatLon{Lat: 40.73, Lon: -74.1}, nil, nil),
+			want: `{"query":{"match_all":{}}}`,
+		},
+		{
+			name: "geo_polygon",
+			b:    NewBuilder().WhereGeoPolygon("location", []geo.Point{triangle[0], triangle[1], triangle[2]}, nil),
+			want: `{"query":{"bool":{"must":[{"geo_polygon":{"location":{"points":[{"lat":1,"lon":2},{"lat":3,"lon":4},{"lat":5,"lon":6}]}}}]}}}`,
+		},
+		{
+			name: "geo_shape envelope uses lon, lat order",
+			b:    NewBuilder().WhereGeoShape("area", geo.Envelope(geo.LatLon{Lat: 40, Lon: -74}, geo.LatLon{Lat: 39, Lon: -73}), "within", nil),
+			want: `{"query":{"bool":{"must":[{"geo_shape":{"area":{"relation":"within","shape":{"type":"envelope","coordinates":[[-74,40],[-73,39]]}}}}]}}}`,
+		},
+		{
+			name: "geo_shape polygon is closed",
+			b:    NewBuilder().WhereNotGeoShape("area", geo.Polygon(triangle...), "", nil),
+			want: `{"query":{"bool":{"must_not":[{"geo_shape":{"area":{"shape":{"type":"polygon","coordinates":[[[2,1],[4,3],[6,5],[2,1]]]}}}}]}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
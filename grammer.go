@@ -6,7 +6,6 @@ import (
 
 func (b *Builder) compile() *esearch.ElasticQuery {
 	query := &esearch.ElasticQuery{
-		Query:      make(esearch.Query),
 		PostFilter: make(esearch.Query),
 	}
 
@@ -30,16 +29,17 @@ func (b *Builder) compile() *esearch.ElasticQuery {
 		query.Collapse = b.collapse
 	}
 
-	if len(b.where) != 0 {
-		boolQuery := b.componentWhere()
-
-		if len(boolQuery.Should) > 0 {
-			boolQuery.MinimumShouldMatch = b.minimumShouldMatch
+	if retriever := b.componentRetriever(); retriever != nil {
+		query.Retriever = retriever
+	} else {
+		// 只有 knn 搜索时, 不设置 query, 避免 match_all 影响得分
+		if len(b.knn) == 0 || b.hasQuery() {
+			query.Query = b.pinnedQuery(b.scoreQuery(b.componentQuery()))
 		}
 
-		query.Query["bool"] = boolQuery
-	} else {
-		query.Query["match_all"] = &esearch.BoolQuery{}
+		if len(b.knn) > 0 {
+			query.Knn = b.knn
+		}
 	}
 
 	if b.postWhere != nil {
@@ -58,6 +58,34 @@ func (b *Builder) compile() *esearch.ElasticQuery {
 	return query
 }
 
+func (b *Builder) hasQuery() bool {
+	return len(b.where) != 0 || b.compoundQuery != nil || b.functionScore != nil || b.scriptScore != nil || b.pinned != nil
+}
+
+func (b *Builder) componentQuery() esearch.Query {
+	query := make(esearch.Query)
+
+	if len(b.where) != 0 {
+		boolQuery := b.componentWhere()
+
+		if len(boolQuery.Should) > 0 {
+			boolQuery.MinimumShouldMatch = b.minimumShouldMatch
+		}
+
+		if b.compoundQuery != nil {
+			boolQuery.Must = append(boolQuery.Must, b.compoundQuery)
+		}
+
+		query["bool"] = boolQuery
+	} else if b.compoundQuery != nil {
+		query = b.compoundQuery
+	} else {
+		query["match_all"] = &esearch.BoolQuery{}
+	}
+
+	return query
+}
+
 func (b *Builder) componentWhere() *esearch.BoolQuery {
 	boolQuery := &esearch.BoolQuery{}
 
@@ -99,6 +127,22 @@ func (b *Builder) componentWhere() *esearch.BoolQuery {
 	return boolQuery
 }
 
+// subQuery 使用新的 Builder 编译闭包中的查询条件, 生成 bool 查询
+func (b *Builder) subQuery(fn NestWhereFunc) esearch.Query {
+	newBuilder := NewBuilder()
+	fn(newBuilder)
+
+	boolQuery := newBuilder.componentWhere()
+	if len(boolQuery.Should) > 0 {
+		boolQuery.MinimumShouldMatch = newBuilder.minimumShouldMatch
+	}
+
+	query := make(esearch.Query)
+	query["bool"] = boolQuery
+
+	return query
+}
+
 func (b *Builder) componentAggs(aggSet map[string]esearch.Aggregator) {
 	for alias, aggregation := range b.aggregations {
 		aggregation.subAggs()
@@ -109,14 +153,7 @@ func (b *Builder) componentAggs(aggSet map[string]esearch.Aggregator) {
 func (aggregation *Aggregation) subAggs() {
 	if aggregation.SubAggs != nil {
 		newAggSet := make(map[string]esearch.Aggregator)
-		for _, subAggFunc := range aggregation.SubAggs {
-			if subAggFunc != nil {
-				newBuilder := NewBuilder()
-				subAggFunc(newBuilder)
-				newBuilder.componentAggs(newAggSet)
-			}
-
-		}
+		aggregation.subBuilder().componentAggs(newAggSet)
 		aggregation.Params.Aggregate(newAggSet)
 	}
 }
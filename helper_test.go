@@ -0,0 +1,48 @@
+package elastic
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// assertDsl 比较 Builder 生成的语句与期望的 json, 忽略键的顺序
+func assertDsl(t *testing.T, b *Builder, want string) {
+	t.Helper()
+
+	dsl, err := b.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	assertJson(t, dsl, want)
+}
+
+func assertJson(t *testing.T, got, want string) {
+	t.Helper()
+
+	var gotValue, wantValue any
+	if err := json.Unmarshal([]byte(got), &gotValue); err != nil {
+		t.Fatalf("unmarshal got %s error = %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("unmarshal want %s error = %v", want, err)
+	}
+
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+// assertPanic fn 必须 panic
+func assertPanic(t *testing.T, fn func()) {
+	t.Helper()
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+
+	fn()
+}
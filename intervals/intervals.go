@@ -0,0 +1,138 @@
+package intervals
+
+import (
+	"encoding/json"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+// Query intervals 查询
+type Query struct {
+	Intervals map[string]Rule `json:"intervals"`
+}
+
+func (q Query) BoolBuild() string {
+	return ""
+}
+
+// Rule intervals 查询的规则, 包括 Match, Prefix, Wildcard, Fuzzy, AllOf, AnyOf
+type Rule interface {
+	IntervalsRule()
+}
+
+// Gaps 设置 MaxGaps, 0 表示词之间不能有间隔, 不设置时 es 默认为 -1, 不限制间隔
+func Gaps(gaps int) *int {
+	return &gaps
+}
+
+type Match struct {
+	Query    string  `json:"query"`
+	MaxGaps  *int    `json:"max_gaps,omitempty"`
+	Ordered  bool    `json:"ordered,omitempty"`
+	Analyzer string  `json:"analyzer,omitempty"`
+	UseField string  `json:"use_field,omitempty"`
+	Filter   *Filter `json:"filter,omitempty"`
+}
+
+func (m Match) MarshalJSON() ([]byte, error) {
+	type match Match
+	return wrap("match", match(m))
+}
+
+type Prefix struct {
+	Prefix   string `json:"prefix"`
+	Analyzer string `json:"analyzer,omitempty"`
+	UseField string `json:"use_field,omitempty"`
+}
+
+func (p Prefix) MarshalJSON() ([]byte, error) {
+	type prefix Prefix
+	return wrap("prefix", prefix(p))
+}
+
+type Wildcard struct {
+	Pattern  string `json:"pattern"`
+	Analyzer string `json:"analyzer,omitempty"`
+	UseField string `json:"use_field,omitempty"`
+}
+
+func (w Wildcard) MarshalJSON() ([]byte, error) {
+	type wildcard Wildcard
+	return wrap("wildcard", wildcard(w))
+}
+
+type Fuzzy struct {
+	Term           string `json:"term"`
+	PrefixLength   int    `json:"prefix_length,omitempty"`
+	Transpositions *bool  `json:"transpositions,omitempty"`
+	Fuzziness      string `json:"fuzziness,omitempty"`
+	Analyzer       string `json:"analyzer,omitempty"`
+	UseField       string `json:"use_field,omitempty"`
+}
+
+func (f Fuzzy) MarshalJSON() ([]byte, error) {
+	type fuzzy Fuzzy
+	return wrap("fuzzy", fuzzy(f))
+}
+
+type AllOf struct {
+	Intervals []Rule  `json:"intervals"`
+	MaxGaps   *int    `json:"max_gaps,omitempty"`
+	Ordered   bool    `json:"ordered,omitempty"`
+	Filter    *Filter `json:"filter,omitempty"`
+}
+
+func (a AllOf) MarshalJSON() ([]byte, error) {
+	type allOf AllOf
+	return wrap("all_of", allOf(a))
+}
+
+type AnyOf struct {
+	Intervals []Rule  `json:"intervals"`
+	Filter    *Filter `json:"filter,omitempty"`
+}
+
+func (a AnyOf) MarshalJSON() ([]byte, error) {
+	type anyOf AnyOf
+	return wrap("any_of", anyOf(a))
+}
+
+// Filter 根据与其他规则的位置关系过滤匹配结果, 每个 Filter 只能设置一个条件
+type Filter struct {
+	After          Rule            `json:"after,omitempty"`
+	Before         Rule            `json:"before,omitempty"`
+	ContainedBy    Rule            `json:"contained_by,omitempty"`
+	Containing     Rule            `json:"containing,omitempty"`
+	NotContainedBy Rule            `json:"not_contained_by,omitempty"`
+	NotContaining  Rule            `json:"not_containing,omitempty"`
+	NotOverlapping Rule            `json:"not_overlapping,omitempty"`
+	Overlapping    Rule            `json:"overlapping,omitempty"`
+	Script         *esearch.Script `json:"script,omitempty"`
+}
+
+func (m Match) IntervalsRule() {
+
+}
+
+func (p Prefix) IntervalsRule() {
+
+}
+
+func (w Wildcard) IntervalsRule() {
+
+}
+
+func (f Fuzzy) IntervalsRule() {
+
+}
+
+func (a AllOf) IntervalsRule() {
+
+}
+
+func (a AnyOf) IntervalsRule() {
+
+}
+
+func wrap(name string, value any) ([]byte, error) {
+	return json.Marshal(map[string]any{name: value})
+}
@@ -0,0 +1,262 @@
+package elastic
+
+import (
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"github.com/KingSolvewer/elasticsearch-query-builder/joining"
+)
+
+// WhereNestedPath Must nested 查询, 对 nested 类型的字段进行查询
+func WhereNestedPath(path string, fn NestWhereFunc, paramFn joining.NestedParamFunc) *Builder {
+	return builder.WhereNestedPath(path, fn, paramFn)
+}
+
+// WhereNestedPath Must nested 查询, 对 nested 类型的字段进行查询
+func (b *Builder) WhereNestedPath(path string, fn NestWhereFunc, paramFn joining.NestedParamFunc) *Builder {
+	b.nestedQuery(esearch.Must, path, fn, paramFn)
+
+	return b
+}
+
+// WhereNotNestedPath MustNot nested 查询
+func WhereNotNestedPath(path string, fn NestWhereFunc, paramFn joining.NestedParamFunc) *Builder {
+	return builder.WhereNotNestedPath(path, fn, paramFn)
+}
+
+// WhereNotNestedPath MustNot nested 查询
+func (b *Builder) WhereNotNestedPath(path string, fn NestWhereFunc, paramFn joining.NestedParamFunc) *Builder {
+	b.nestedQuery(esearch.MustNot, path, fn, paramFn)
+
+	return b
+}
+
+// OrWhereNestedPath Should nested 查询
+func OrWhereNestedPath(path string, fn NestWhereFunc, paramFn joining.NestedParamFunc) *Builder {
+	return builder.OrWhereNestedPath(path, fn, paramFn)
+}
+
+// OrWhereNestedPath Should nested 查询
+func (b *Builder) OrWhereNestedPath(path string, fn NestWhereFunc, paramFn joining.NestedParamFunc) *Builder {
+	b.nestedQuery(esearch.Should, path, fn, paramFn)
+
+	return b
+}
+
+// FilterNestedPath Filter nested 查询
+func FilterNestedPath(path string, fn NestWhereFunc, paramFn joining.NestedParamFunc) *Builder {
+	return builder.FilterNestedPath(path, fn, paramFn)
+}
+
+// FilterNestedPath Filter nested 查询
+func (b *Builder) FilterNestedPath(path string, fn NestWhereFunc, paramFn joining.NestedParamFunc) *Builder {
+	b.nestedQuery(esearch.FilterClause, path, fn, paramFn)
+
+	return b
+}
+
+// WhereHasChild Must has_child 查询, 查询子文档满足条件的父文档
+func WhereHasChild(typ string, fn NestWhereFunc, paramFn joining.HasChildParamFunc) *Builder {
+	return builder.WhereHasChild(typ, fn, paramFn)
+}
+
+// WhereHasChild Must has_child 查询, 查询子文档满足条件的父文档
+func (b *Builder) WhereHasChild(typ string, fn NestWhereFunc, paramFn joining.HasChildParamFunc) *Builder {
+	b.hasChild(esearch.Must, typ, fn, paramFn)
+
+	return b
+}
+
+// WhereNotHasChild MustNot has_child 查询
+func WhereNotHasChild(typ string, fn NestWhereFunc, paramFn joining.HasChildParamFunc) *Builder {
+	return builder.WhereNotHasChild(typ, fn, paramFn)
+}
+
+// WhereNotHasChild MustNot has_child 查询
+func (b *Builder) WhereNotHasChild(typ string, fn NestWhereFunc, paramFn joining.HasChildParamFunc) *Builder {
+	b.hasChild(esearch.MustNot, typ, fn, paramFn)
+
+	return b
+}
+
+// OrWhereHasChild Should has_child 查询
+func OrWhereHasChild(typ string, fn NestWhereFunc, paramFn joining.HasChildParamFunc) *Builder {
+	return builder.OrWhereHasChild(typ, fn, paramFn)
+}
+
+// OrWhereHasChild Should has_child 查询
+func (b *Builder) OrWhereHasChild(typ string, fn NestWhereFunc, paramFn joining.HasChildParamFunc) *Builder {
+	b.hasChild(esearch.Should, typ, fn, paramFn)
+
+	return b
+}
+
+// FilterHasChild Filter has_child 查询
+func FilterHasChild(typ string, fn NestWhereFunc, paramFn joining.HasChildParamFunc) *Builder {
+	return builder.FilterHasChild(typ, fn, paramFn)
+}
+
+// FilterHasChild Filter has_child 查询
+func (b *Builder) FilterHasChild(typ string, fn NestWhereFunc, paramFn joining.HasChildParamFunc) *Builder {
+	b.hasChild(esearch.FilterClause, typ, fn, paramFn)
+
+	return b
+}
+
+// WhereHasParent Must has_parent 查询, 查询父文档满足条件的子文档
+func WhereHasParent(parentType string, fn NestWhereFunc, paramFn joining.HasParentParamFunc) *Builder {
+	return builder.WhereHasParent(parentType, fn, paramFn)
+}
+
+// WhereHasParent Must has_parent 查询, 查询父文档满足条件的子文档
+func (b *Builder) WhereHasParent(parentType string, fn NestWhereFunc, paramFn joining.HasParentParamFunc) *Builder {
+	b.hasParent(esearch.Must, parentType, fn, paramFn)
+
+	return b
+}
+
+// WhereNotHasParent MustNot has_parent 查询
+func WhereNotHasParent(parentType string, fn NestWhereFunc, paramFn joining.HasParentParamFunc) *Builder {
+	return builder.WhereNotHasParent(parentType, fn, paramFn)
+}
+
+// WhereNotHasParent MustNot has_parent 查询
+func (b *Builder) WhereNotHasParent(parentType string, fn NestWhereFunc, paramFn joining.HasParentParamFunc) *Builder {
+	b.hasParent(esearch.MustNot, parentType, fn, paramFn)
+
+	return b
+}
+
+// OrWhereHasParent Should has_parent 查询
+func OrWhereHasParent(parentType string, fn NestWhereFunc, paramFn joining.HasParentParamFunc) *Builder {
+	return builder.OrWhereHasParent(parentType, fn, paramFn)
+}
+
+// OrWhereHasParent Should has_parent 查询
+func (b *Builder) OrWhereHasParent(parentType string, fn NestWhereFunc, paramFn joining.HasParentParamFunc) *Builder {
+	b.hasParent(esearch.Should, parentType, fn, paramFn)
+
+	return b
+}
+
+// FilterHasParent Filter has_parent 查询
+func FilterHasParent(parentType string, fn NestWhereFunc, paramFn joining.HasParentParamFunc) *Builder {
+	return builder.FilterHasParent(parentType, fn, paramFn)
+}
+
+// FilterHasParent Filter has_parent 查询
+func (b *Builder) FilterHasParent(parentType string, fn NestWhereFunc, paramFn joining.HasParentParamFunc) *Builder {
+	b.hasParent(esearch.FilterClause, parentType, fn, paramFn)
+
+	return b
+}
+
+// WhereParentId Must parent_id 查询, 查询指定父文档的子文档
+func WhereParentId(typ string, id string) *Builder {
+	return builder.WhereParentId(typ, id)
+}
+
+// WhereParentId Must parent_id 查询, 查询指定父文档的子文档
+func (b *Builder) WhereParentId(typ string, id string) *Builder {
+	b.parentId(esearch.Must, typ, id)
+
+	return b
+}
+
+// WhereNotParentId MustNot parent_id 查询
+func WhereNotParentId(typ string, id string) *Builder {
+	return builder.WhereNotParentId(typ, id)
+}
+
+// WhereNotParentId MustNot parent_id 查询
+func (b *Builder) WhereNotParentId(typ string, id string) *Builder {
+	b.parentId(esearch.MustNot, typ, id)
+
+	return b
+}
+
+// OrWhereParentId Should parent_id 查询
+func OrWhereParentId(typ string, id string) *Builder {
+	return builder.OrWhereParentId(typ, id)
+}
+
+// OrWhereParentId Should parent_id 查询
+func (b *Builder) OrWhereParentId(typ string, id string) *Builder {
+	b.parentId(esearch.Should, typ, id)
+
+	return b
+}
+
+// FilterParentId Filter parent_id 查询
+func FilterParentId(typ string, id string) *Builder {
+	return builder.FilterParentId(typ, id)
+}
+
+// FilterParentId Filter parent_id 查询
+func (b *Builder) FilterParentId(typ string, id string) *Builder {
+	b.parentId(esearch.FilterClause, typ, id)
+
+	return b
+}
+
+func (b *Builder) nestedQuery(clauseTyp esearch.BoolClauseType, path string, fn NestWhereFunc, paramFn joining.NestedParamFunc) {
+	if path == "" || fn == nil {
+		return
+	}
+
+	nested := joining.Nested{
+		Path:  path,
+		Query: b.subQuery(fn),
+	}
+
+	if paramFn != nil {
+		nested.NestedParam = paramFn()
+	}
+
+	b.append(clauseTyp, joining.NestedQuery{Nested: nested})
+}
+
+func (b *Builder) hasChild(clauseTyp esearch.BoolClauseType, typ string, fn NestWhereFunc, paramFn joining.HasChildParamFunc) {
+	if typ == "" || fn == nil {
+		return
+	}
+
+	hasChild := joining.HasChild{
+		Type:  typ,
+		Query: b.subQuery(fn),
+	}
+
+	if paramFn != nil {
+		hasChild.HasChildParam = paramFn()
+	}
+
+	b.append(clauseTyp, joining.HasChildQuery{HasChild: hasChild})
+}
+
+func (b *Builder) hasParent(clauseTyp esearch.BoolClauseType, parentType string, fn NestWhereFunc, paramFn joining.HasParentParamFunc) {
+	if parentType == "" || fn == nil {
+		return
+	}
+
+	hasParent := joining.HasParent{
+		ParentType: parentType,
+		Query:      b.subQuery(fn),
+	}
+
+	if paramFn != nil {
+		hasParent.HasParentParam = paramFn()
+	}
+
+	b.append(clauseTyp, joining.HasParentQuery{HasParent: hasParent})
+}
+
+func (b *Builder) parentId(clauseTyp esearch.BoolClauseType, typ string, id string) {
+	if typ == "" || id == "" {
+		return
+	}
+
+	b.append(clauseTyp, joining.ParentIdQuery{
+		ParentId: joining.ParentId{
+			Type: typ,
+			Id:   id,
+		},
+	})
+}
@@ -0,0 +1,105 @@
+package joining
+
+import (
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+type ScoreMode string
+
+const (
+	ScoreAvg  ScoreMode = "avg"
+	ScoreMax  ScoreMode = "max"
+	ScoreMin  ScoreMode = "min"
+	ScoreNone ScoreMode = "none"
+	ScoreSum  ScoreMode = "sum"
+)
+
+type NestedQuery struct {
+	Nested Nested `json:"nested"`
+}
+
+type Nested struct {
+	Path  string        `json:"path"`
+	Query esearch.Query `json:"query"`
+	NestedParam
+}
+
+type NestedParamFunc func() NestedParam
+
+type NestedParam struct {
+	ScoreMode      ScoreMode  `json:"score_mode,omitempty"`
+	IgnoreUnmapped bool       `json:"ignore_unmapped,omitempty"`
+	InnerHits      *InnerHits `json:"inner_hits,omitempty"`
+}
+
+// InnerHits 返回命中的嵌套文档或父子文档, 空结构体对应 "inner_hits": {}
+type InnerHits struct {
+	Name   string           `json:"name,omitempty"`
+	From   uint             `json:"from,omitempty"`
+	Size   uint             `json:"size,omitempty"`
+	Sort   []esearch.Sorter `json:"sort,omitempty"`
+	Source []string         `json:"_source,omitempty"`
+}
+
+func (nested NestedQuery) BoolBuild() string {
+	return ""
+}
+
+type HasChildQuery struct {
+	HasChild HasChild `json:"has_child"`
+}
+
+type HasChild struct {
+	Type  string        `json:"type"`
+	Query esearch.Query `json:"query"`
+	HasChildParam
+}
+
+type HasChildParamFunc func() HasChildParam
+
+type HasChildParam struct {
+	ScoreMode      ScoreMode  `json:"score_mode,omitempty"`
+	MinChildren    int        `json:"min_children,omitempty"`
+	MaxChildren    int        `json:"max_children,omitempty"`
+	IgnoreUnmapped bool       `json:"ignore_unmapped,omitempty"`
+	InnerHits      *InnerHits `json:"inner_hits,omitempty"`
+}
+
+func (child HasChildQuery) BoolBuild() string {
+	return ""
+}
+
+type HasParentQuery struct {
+	HasParent HasParent `json:"has_parent"`
+}
+
+type HasParent struct {
+	ParentType string        `json:"parent_type"`
+	Query      esearch.Query `json:"query"`
+	HasParentParam
+}
+
+type HasParentParamFunc func() HasParentParam
+
+type HasParentParam struct {
+	Score          bool       `json:"score,omitempty"` // 是否使用父文档的相关性得分
+	IgnoreUnmapped bool       `json:"ignore_unmapped,omitempty"`
+	InnerHits      *InnerHits `json:"inner_hits,omitempty"`
+}
+
+func (parent HasParentQuery) BoolBuild() string {
+	return ""
+}
+
+type ParentIdQuery struct {
+	ParentId ParentId `json:"parent_id"`
+}
+
+type ParentId struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+}
+
+func (parent ParentIdQuery) BoolBuild() string {
+	return ""
+}
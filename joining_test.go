@@ -0,0 +1,98 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/joining"
+)
+
+func TestNestedPath(t *testing.T) {
+	author := func(b *Builder) {
+		b.Where("comments.author", "kim")
+	}
+
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "must",
+			b:    NewBuilder().WhereNestedPath("comments", author, nil),
+			want: `{"query":{"bool":{"must":[{"nested":{"path":"comments","query":{"bool":{"must":[{"term":{"comments.author":"kim"}}]}}}}]}}}`,
+		},
+		{
+			name: "must_not with params",
+			b: NewBuilder().WhereNotNestedPath("comments", author, func() joining.NestedParam {
+				return joining.NestedParam{ScoreMode: joining.ScoreMax, InnerHits: &joining.InnerHits{}}
+			}),
+			want: `{"query":{"bool":{"must_not":[{"nested":{"path":"comments","query":{"bool":{"must":[{"term":{"comments.author":"kim"}}]}},"score_mode":"max","inner_hits":{}}}]}}}`,
+		},
+		{
+			name: "should keeps minimum_should_match of sub query",
+			b: NewBuilder().OrWhereNestedPath("comments", func(b *Builder) {
+				b.OrWhere("comments.author", "kim").OrWhere("comments.author", "lee").MinimumShouldMatch(1)
+			}, nil),
+			want: `{"query":{"bool":{"should":[{"nested":{"path":"comments","query":{"bool":{"should":[{"term":{"comments.author":"kim"}},{"term":{"comments.author":"lee"}}],"minimum_should_match":1}}}}]}}}`,
+		},
+		{
+			name: "filter",
+			b:    NewBuilder().FilterNestedPath("comments", author, nil),
+			want: `{"query":{"bool":{"filter":[{"nested":{"path":"comments","query":{"bool":{"must":[{"term":{"comments.author":"kim"}}]}}}}]}}}`,
+		},
+		{
+			name: "empty path is skipped",
+			b:    NewBuilder().WhereNestedPath("", author, nil),
+			want: `{"query":{"match_all":{}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestParentChild(t *testing.T) {
+	answer := func(b *Builder) {
+		b.Where("status", "accepted")
+	}
+
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "has_child",
+			b: NewBuilder().WhereHasChild("answer", answer, func() joining.HasChildParam {
+				return joining.HasChildParam{ScoreMode: joining.ScoreSum, MinChildren: 1, MaxChildren: 10}
+			}),
+			want: `{"query":{"bool":{"must":[{"has_child":{"type":"answer","query":{"bool":{"must":[{"term":{"status":"accepted"}}]}},"score_mode":"sum","min_children":1,"max_children":10}}]}}}`,
+		},
+		{
+			name: "has_parent",
+			b: NewBuilder().FilterHasParent("question", answer, func() joining.HasParentParam {
+				return joining.HasParentParam{Score: true, InnerHits: &joining.InnerHits{Size: 1}}
+			}),
+			want: `{"query":{"bool":{"filter":[{"has_parent":{"parent_type":"question","query":{"bool":{"must":[{"term":{"status":"accepted"}}]}},"score":true,"inner_hits":{"size":1}}}]}}}`,
+		},
+		{
+			name: "parent_id",
+			b:    NewBuilder().OrWhereParentId("answer", "1").WhereNotParentId("answer", "2"),
+			want: `{"query":{"bool":{"must_not":[{"parent_id":{"type":"answer","id":"2"}}],"should":[{"parent_id":{"type":"answer","id":"1"}}]}}}`,
+		},
+		{
+			name: "empty type is skipped",
+			b:    NewBuilder().WhereHasChild("", answer, nil).WhereParentId("answer", ""),
+			want: `{"query":{"match_all":{}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package elastic
+
+import (
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+// Knn 近似 k 近邻搜索, 可以多次调用, 与 Builder 中的查询条件同时使用时, 得分相加实现混合检索
+func Knn(field string, vector []float32, k int, numCandidates int, filterFn NestWhereFunc) *Builder {
+	return builder.Knn(field, vector, k, numCandidates, filterFn)
+}
+
+// Knn 近似 k 近邻搜索, 可以多次调用, 与 Builder 中的查询条件同时使用时, 得分相加实现混合检索
+func (b *Builder) Knn(field string, vector []float32, k int, numCandidates int, filterFn NestWhereFunc) *Builder {
+	return b.KnnParams(field, vector, k, numCandidates, filterFn, nil)
+}
+
+// KnnParams 近似 k 近邻搜索, 支持 similarity, boost 参数
+func KnnParams(field string, vector []float32, k int, numCandidates int, filterFn NestWhereFunc, paramFn esearch.KnnParamFunc) *Builder {
+	return builder.KnnParams(field, vector, k, numCandidates, filterFn, paramFn)
+}
+
+// KnnParams 近似 k 近邻搜索, 支持 similarity, boost 参数
+func (b *Builder) KnnParams(field string, vector []float32, k int, numCandidates int, filterFn NestWhereFunc, paramFn esearch.KnnParamFunc) *Builder {
+	if field == "" || len(vector) == 0 || k <= 0 {
+		return b
+	}
+
+	if numCandidates < k {
+		numCandidates = k
+	}
+
+	knn := esearch.Knn{
+		Field:         field,
+		QueryVector:   vector,
+		K:             k,
+		NumCandidates: numCandidates,
+	}
+
+	if filterFn != nil {
+		knn.Filter = b.subQuery(filterFn)
+	}
+
+	if paramFn != nil {
+		knn.KnnParam = paramFn()
+	}
+
+	b.knn = append(b.knn, knn)
+
+	return b
+}
@@ -0,0 +1,47 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/compound"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+func TestKnn(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "knn with query and filter, num_candidates raised to k",
+			b: NewBuilder().Where("a", 1).Knn("vec", []float32{0.1, 0.2}, 10, 5, func(b *Builder) {
+				b.Where("lang", "zh")
+			}),
+			want: `{"query":{"bool":{"must":[{"term":{"a":1}}]}},"knn":[{"field":"vec","query_vector":[0.1,0.2],"k":10,"num_candidates":10,"filter":{"bool":{"must":[{"term":{"lang":"zh"}}]}}}]}`,
+		},
+		{
+			name: "multiple knn with params, no query",
+			b: NewBuilder().KnnParams("vec", []float32{0.1}, 3, 50, nil, func() esearch.KnnParam {
+				return esearch.KnnParam{Similarity: 0.8, Boost: 2}
+			}).Knn("img", []float32{1}, 2, 2, nil),
+			want: `{"knn":[{"field":"vec","query_vector":[0.1],"k":3,"num_candidates":50,"similarity":0.8,"boost":2},{"field":"img","query_vector":[1],"k":2,"num_candidates":2}]}`,
+		},
+		{
+			name: "empty vector is skipped",
+			b:    NewBuilder().Knn("vec", nil, 3, 3, nil),
+			want: `{"query":{"match_all":{}}}`,
+		},
+		{
+			name: "script_score similarity fallback",
+			b:    NewBuilder().ScriptScore(nil, compound.CosineSimilarity("vec", []float32{0.5}), nil),
+			want: `{"query":{"script_score":{"query":{"match_all":{}},"script":{"source":"cosineSimilarity(params.query_vector, 'vec') + 1.0","params":{"query_vector":[0.5]}}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
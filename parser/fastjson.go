@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
@@ -16,150 +17,48 @@ func AggValueParser(obj *fastjson.Object, dest any) (aggsResult *esearch.AggsRes
 	errorSet = make([]error, 0)
 
 	obj.Visit(func(k []byte, v *fastjson.Value) {
-		key := string(k)
-
-		lastIndex := strings.LastIndex(key, "_")
-
-		if lastIndex != -1 && lastIndex+1 < len(key) {
-			lastString := key[lastIndex:]
-			switch lastString {
-			case esearch.Terms:
-				bucketArr := v.GetArray("buckets")
-
-				buckets := make([]esearch.Bucket, len(bucketArr))
-				for i, item := range bucketArr {
-					bucketObj := item.GetObject()
-
-					var bucket esearch.Bucket
-					if bucketObj != nil {
-						bucket, errorSet = subAggParser(bucketObj, dest)
-					}
-					buckets[i] = bucket
-				}
-				aggsResult.Terms = make(map[string]*esearch.TermsResult)
-				aggsResult.Terms[key] = &esearch.TermsResult{
-					DocCountErrorUpperBound: v.GetInt("doc_count_error_upper_bound"),
-					SumOtherDocCount:        v.GetInt("sum_other_doc_count"),
-					Buckets:                 buckets,
-				}
-			case esearch.Histogram, esearch.DateHistogram:
-				bucketsArr := v.GetArray("buckets")
-
-				buckets := make([]esearch.Bucket, len(bucketsArr))
-				for i, item := range bucketsArr {
-					bucketObj := item.GetObject()
-
-					var bucket esearch.Bucket
-					if bucketObj != nil {
-						bucket, errorSet = subAggParser(bucketObj, dest)
-					}
-
-					buckets[i] = bucket
-
-				}
-				aggsResult.Histogram = make(map[string]*esearch.HistogramResult)
-				aggsResult.Histogram[key] = &esearch.HistogramResult{
-					Buckets: buckets,
-				}
-			case esearch.Range, esearch.DateRange:
-				bucketsArr := v.GetArray("buckets")
-
-				buckets := make([]esearch.Bucket, len(bucketsArr))
-				for i, item := range bucketsArr {
-					bucketObj := item.GetObject()
-
-					var bucket esearch.Bucket
-					if bucketObj != nil {
-						bucket, errorSet = subAggParser(bucketObj, dest)
-					}
-
-					buckets[i] = bucket
-				}
-
-				aggsResult.Range = make(map[string]*esearch.RangeResult)
-				aggsResult.Range[key] = &esearch.RangeResult{
-					Buckets: buckets,
-				}
-			case esearch.Cardinality, esearch.ValueCount:
-				aggsResult.Count = make(map[string]*esearch.CountResult)
-				aggsResult.Count[key] = &esearch.CountResult{Value: v.GetInt("value")}
-			case esearch.Avg, esearch.Max, esearch.Min, esearch.Sum:
-				aggsResult.Arithmetic = make(map[string]*esearch.ArithmeticResult)
-				aggsResult.Arithmetic[key] = &esearch.ArithmeticResult{Value: v.GetFloat64("value")}
-			case esearch.Stats:
-				aggsResult.Stats = make(map[string]*esearch.StatsResult)
-				aggsResult.Stats[key] = &esearch.StatsResult{
-					Count: v.GetInt("count"),
-					Max:   v.GetFloat64("max"),
-					Min:   v.GetFloat64("min"),
-					Sum:   v.GetFloat64("sum"),
-					Avg:   v.GetFloat64("avg"),
-				}
-			case esearch.ExtendedStats:
-				aggsResult.ExtendedStats = make(map[string]*esearch.ExtendStatsResult)
-				stdDeviationBoundsV := v.Get("std_deviation_bounds")
-				stdDeviationBounds := esearch.StdDeviationBounds{
-					Upper: stdDeviationBoundsV.GetFloat64("upper"),
-					Lower: stdDeviationBoundsV.GetFloat64("lower"),
-				}
-
-				aggsResult.ExtendedStats[key] = &esearch.ExtendStatsResult{
-					StatsResult: &esearch.StatsResult{
-						Count: v.GetInt("count"),
-						Max:   v.GetFloat64("max"),
-						Min:   v.GetFloat64("min"),
-						Sum:   v.GetFloat64("sum"),
-						Avg:   v.GetFloat64("avg"),
-					},
-					SumOfSquares:       v.GetFloat64("sum_of_squares"),
-					Variance:           v.GetFloat64("variance"),
-					StdDeviation:       v.GetFloat64("std_deviation"),
-					StdDeviationBounds: stdDeviationBounds,
-				}
-			case esearch.TopHits:
-				topHitsV := v.Get("hits")
-
-				hitsArr := topHitsV.GetArray("hits")
-				hitsBuckets := make([]*esearch.HitsBucket, len(hitsArr))
-				for i, hitsV := range hitsArr {
-					newDest, err := topHitsParser(hitsV, dest)
-					if err != nil {
-						errorSet = append(errorSet, err)
-					}
-
-					hitsBuckets[i] = &esearch.HitsBucket{
-						Source: newDest,
-					}
-				}
-				aggsResult.TopHits = &esearch.HitsResult{
-					Total: topHitsV.GetInt("total"),
-					Hits:  hitsBuckets,
-				}
-			}
-		}
+		errorSet = append(errorSet, aggParser(aggsResult, string(k), v, dest)...)
 	})
 
-	return aggsResult, nil
+	return aggsResult, errorSet
 }
 
 func subAggParser(obj *fastjson.Object, dest any) (rootBucket esearch.Bucket, errorSet []error) {
 	errorSet = make([]error, 0)
 	rootBucket = esearch.Bucket{
 		Aggs: esearch.AggsResult{
-			Terms:         make(map[string]*esearch.TermsResult),
-			Histogram:     make(map[string]*esearch.HistogramResult),
-			Range:         make(map[string]*esearch.RangeResult),
-			Count:         make(map[string]*esearch.CountResult),
-			Arithmetic:    make(map[string]*esearch.ArithmeticResult),
-			Stats:         make(map[string]*esearch.StatsResult),
-			ExtendedStats: make(map[string]*esearch.ExtendStatsResult),
-			TopHits:       &esearch.HitsResult{},
+			Terms:                   make(map[string]*esearch.TermsResult),
+			Histogram:               make(map[string]*esearch.HistogramResult),
+			Range:                   make(map[string]*esearch.RangeResult),
+			Count:                   make(map[string]*esearch.CountResult),
+			Arithmetic:              make(map[string]*esearch.ArithmeticResult),
+			Stats:                   make(map[string]*esearch.StatsResult),
+			ExtendedStats:           make(map[string]*esearch.ExtendStatsResult),
+			TopHits:                 &esearch.HitsResult{},
+			Percentiles:             make(map[string]*esearch.PercentilesResult),
+			Composite:               make(map[string]*esearch.CompositeResult),
+			SingleBucket:            make(map[string]*esearch.Bucket),
+			Filters:                 make(map[string]*esearch.FiltersResult),
+			Significant:             make(map[string]*esearch.SignificantResult),
+			GeoGrid:                 make(map[string]*esearch.GeoGridResult),
+			GeoBounds:               make(map[string]*esearch.GeoBoundsResult),
+			GeoCentroid:             make(map[string]*esearch.GeoCentroidResult),
+			MedianAbsoluteDeviation: make(map[string]*esearch.ArithmeticResult),
 		},
 	}
 	obj.Visit(func(k []byte, v *fastjson.Value) {
 		key := string(k)
 		if key == "key" {
-			rootBucket.Key = string(v.GetStringBytes())
+			switch v.Type() {
+			case fastjson.TypeObject:
+				rootBucket.CompositeKey, _ = ConvertRawValue(v).(map[string]any)
+			case fastjson.TypeArray:
+				rootBucket.Keys, _ = ConvertRawValue(v).([]any)
+			case fastjson.TypeNumber:
+				rootBucket.Key = string(v.MarshalTo(nil))
+			default:
+				rootBucket.Key = string(v.GetStringBytes())
+			}
 		} else if key == "doc_count" {
 			rootBucket.DocCount = v.GetInt()
 		} else if key == "key_as_string" {
@@ -168,121 +67,364 @@ func subAggParser(obj *fastjson.Object, dest any) (rootBucket esearch.Bucket, er
 			rootBucket.To = v.GetFloat64()
 		} else if key == "from" {
 			rootBucket.From = v.GetFloat64()
+		} else if key == "min" {
+			rootBucket.Min = v.GetFloat64()
+		} else if key == "max" {
+			rootBucket.Max = v.GetFloat64()
 		} else {
-			lastIndex := strings.LastIndex(key, "_")
-			if lastIndex != -1 && lastIndex+1 < len(key) {
-				lastString := key[lastIndex:]
-				switch lastString {
-				case esearch.Terms:
-					bucketsArr := v.GetArray("buckets")
-
-					subBuckets := make([]esearch.Bucket, len(bucketsArr))
-					for i, bucket := range bucketsArr {
-						bucketObj := bucket.GetObject()
-
-						var subBucket esearch.Bucket
-						if bucketObj != nil {
-							subBucket, errorSet = subAggParser(bucketObj, dest)
-						}
-						subBuckets[i] = subBucket
-					}
-					rootBucket.Aggs.Terms[key] = &esearch.TermsResult{
-						DocCountErrorUpperBound: v.GetInt("doc_count_error_upper_bound"),
-						SumOtherDocCount:        v.GetInt("sum_other_doc_count"),
-						Buckets:                 subBuckets,
-					}
-				case esearch.Histogram, esearch.DateHistogram:
-					bucketsArr := v.GetArray("buckets")
+			errorSet = append(errorSet, aggParser(&rootBucket.Aggs, key, v, dest)...)
+		}
+	})
+	return
+}
 
-					buckets := make([]esearch.Bucket, len(bucketsArr))
-					for i, item := range bucketsArr {
-						bucketObj := item.GetObject()
+// aggParser 根据聚合名称的后缀, 解析聚合结果
+func aggParser(aggsResult *esearch.AggsResult, key string, v *fastjson.Value, dest any) (errorSet []error) {
+	lastIndex := strings.LastIndex(key, "_")
+	if lastIndex == -1 || lastIndex+1 >= len(key) {
+		return nil
+	}
 
-						var bucket esearch.Bucket
-						if bucketObj != nil {
-							bucket, errorSet = subAggParser(bucketObj, dest)
-						}
+	lastString := key[lastIndex:]
+	switch lastString {
+	case esearch.Terms, esearch.RareTerms, esearch.MultiTerms:
+		var buckets []esearch.Bucket
+		buckets, errorSet = bucketsParser(v.GetArray("buckets"), dest)
 
-						buckets[i] = bucket
-					}
+		if aggsResult.Terms == nil {
+			aggsResult.Terms = make(map[string]*esearch.TermsResult)
+		}
+		aggsResult.Terms[key] = &esearch.TermsResult{
+			DocCountErrorUpperBound: v.GetInt("doc_count_error_upper_bound"),
+			SumOtherDocCount:        v.GetInt("sum_other_doc_count"),
+			Buckets:                 buckets,
+		}
+	case esearch.Histogram, esearch.DateHistogram, esearch.AutoDateHistogram, esearch.VariableWidthHistogram:
+		var buckets []esearch.Bucket
+		buckets, errorSet = keyedBucketsParser(v.Get("buckets"), dest)
 
-					rootBucket.Aggs.Histogram[key] = &esearch.HistogramResult{
-						Buckets: buckets,
-					}
-				case esearch.Range, esearch.DateRange:
-					bucketsArr := v.GetArray("buckets")
+		if aggsResult.Histogram == nil {
+			aggsResult.Histogram = make(map[string]*esearch.HistogramResult)
+		}
+		aggsResult.Histogram[key] = &esearch.HistogramResult{
+			Buckets:  buckets,
+			Interval: string(v.GetStringBytes("interval")),
+		}
+	case esearch.Range, esearch.DateRange, esearch.GeoDistance:
+		var buckets []esearch.Bucket
+		buckets, errorSet = keyedBucketsParser(v.Get("buckets"), dest)
 
-					buckets := make([]esearch.Bucket, len(bucketsArr))
-					for i, item := range bucketsArr {
-						bucketObj := item.GetObject()
+		if aggsResult.Range == nil {
+			aggsResult.Range = make(map[string]*esearch.RangeResult)
+		}
+		aggsResult.Range[key] = &esearch.RangeResult{
+			Buckets: buckets,
+		}
+	case esearch.Cardinality, esearch.ValueCount:
+		if aggsResult.Count == nil {
+			aggsResult.Count = make(map[string]*esearch.CountResult)
+		}
+		aggsResult.Count[key] = &esearch.CountResult{Value: v.GetInt("value")}
+	case esearch.Avg, esearch.Max, esearch.Min, esearch.Sum,
+		esearch.Derivative, esearch.CumulativeSum, esearch.MovingFn, esearch.SerialDiff,
+		esearch.AvgBucket, esearch.SumBucket, esearch.MaxBucket, esearch.MinBucket, esearch.BucketScript:
+		if aggsResult.Arithmetic == nil {
+			aggsResult.Arithmetic = make(map[string]*esearch.ArithmeticResult)
+		}
+		arithmeticResult := &esearch.ArithmeticResult{Value: v.GetFloat64("value")}
+		if lastString == esearch.MaxBucket || lastString == esearch.MinBucket {
+			keysArr := v.GetArray("keys")
+			arithmeticResult.Keys = make([]string, 0, len(keysArr))
+			for _, keyV := range keysArr {
+				bucketKey, err := GetString(keyV)
+				if err != nil {
+					errorSet = append(errorSet, err)
+					continue
+				}
+				arithmeticResult.Keys = append(arithmeticResult.Keys, bucketKey)
+			}
+		}
+		aggsResult.Arithmetic[key] = arithmeticResult
+	case esearch.MedianAbsoluteDeviation:
+		if aggsResult.MedianAbsoluteDeviation == nil {
+			aggsResult.MedianAbsoluteDeviation = make(map[string]*esearch.ArithmeticResult)
+		}
+		aggsResult.MedianAbsoluteDeviation[key] = &esearch.ArithmeticResult{Value: v.GetFloat64("value")}
+	case esearch.Stats, esearch.StatsBucket:
+		if aggsResult.Stats == nil {
+			aggsResult.Stats = make(map[string]*esearch.StatsResult)
+		}
+		aggsResult.Stats[key] = &esearch.StatsResult{
+			Count: v.GetInt("count"),
+			Max:   v.GetFloat64("max"),
+			Min:   v.GetFloat64("min"),
+			Sum:   v.GetFloat64("sum"),
+			Avg:   v.GetFloat64("avg"),
+		}
+	case esearch.ExtendedStats:
+		stdDeviationBoundsV := v.Get("std_deviation_bounds")
+		stdDeviationBounds := esearch.StdDeviationBounds{
+			Upper: stdDeviationBoundsV.GetFloat64("upper"),
+			Lower: stdDeviationBoundsV.GetFloat64("lower"),
+		}
 
-						var bucket esearch.Bucket
-						if bucketObj != nil {
-							bucket, errorSet = subAggParser(bucketObj, dest)
-						}
+		if aggsResult.ExtendedStats == nil {
+			aggsResult.ExtendedStats = make(map[string]*esearch.ExtendStatsResult)
+		}
+		aggsResult.ExtendedStats[key] = &esearch.ExtendStatsResult{
+			StatsResult: &esearch.StatsResult{
+				Count: v.GetInt("count"),
+				Max:   v.GetFloat64("max"),
+				Min:   v.GetFloat64("min"),
+				Sum:   v.GetFloat64("sum"),
+				Avg:   v.GetFloat64("avg"),
+			},
+			SumOfSquares:       v.GetFloat64("sum_of_squares"),
+			Variance:           v.GetFloat64("variance"),
+			StdDeviation:       v.GetFloat64("std_deviation"),
+			StdDeviationBounds: stdDeviationBounds,
+		}
+	case esearch.Composite:
+		var buckets []esearch.Bucket
+		buckets, errorSet = bucketsParser(v.GetArray("buckets"), dest)
 
-						buckets[i] = bucket
-					}
+		if aggsResult.Composite == nil {
+			aggsResult.Composite = make(map[string]*esearch.CompositeResult)
+		}
 
-					rootBucket.Aggs.Range[key] = &esearch.RangeResult{
-						Buckets: buckets,
-					}
-				case esearch.Cardinality, esearch.ValueCount:
-					rootBucket.Aggs.Count[key] = &esearch.CountResult{Value: v.GetInt("value")}
-				case esearch.Avg, esearch.Max, esearch.Min, esearch.Sum:
-					rootBucket.Aggs.Arithmetic[key] = &esearch.ArithmeticResult{Value: v.GetFloat64("value")}
-				case esearch.Stats:
-					rootBucket.Aggs.Stats[key] = &esearch.StatsResult{
-						Count: v.GetInt("count"),
-						Max:   v.GetFloat64("max"),
-						Min:   v.GetFloat64("min"),
-						Sum:   v.GetFloat64("sum"),
-						Avg:   v.GetFloat64("avg"),
-					}
-				case esearch.ExtendedStats:
-					stdDeviationBoundsV := v.Get("std_deviation_bounds")
-					stdDeviationBounds := esearch.StdDeviationBounds{
-						Upper: stdDeviationBoundsV.GetFloat64("upper"),
-						Lower: stdDeviationBoundsV.GetFloat64("lower"),
-					}
+		compositeResult := &esearch.CompositeResult{
+			Buckets: buckets,
+		}
+		if afterKeyV := v.Get("after_key"); afterKeyV != nil {
+			compositeResult.AfterKey, _ = ConvertRawValue(afterKeyV).(map[string]any)
+		}
+		aggsResult.Composite[key] = compositeResult
+	case esearch.AggsFilter, esearch.Nested, esearch.ReverseNested,
+		esearch.Sampler, esearch.DiversifiedSampler, esearch.RandomSampler:
+		var bucket esearch.Bucket
+		bucket, errorSet = subAggParser(v.GetObject(), dest)
+
+		if aggsResult.SingleBucket == nil {
+			aggsResult.SingleBucket = make(map[string]*esearch.Bucket)
+		}
+		aggsResult.SingleBucket[key] = &bucket
+	case esearch.SignificantTerms, esearch.SignificantText:
+		bucketsArr := v.GetArray("buckets")
+		buckets := make([]esearch.SignificantBucket, len(bucketsArr))
+		for i, item := range bucketsArr {
+			var errs []error
+			if bucketObj := item.GetObject(); bucketObj != nil {
+				buckets[i].Bucket, errs = subAggParser(bucketObj, dest)
+				errorSet = append(errorSet, errs...)
+			}
+			buckets[i].Score = item.GetFloat64("score")
+			buckets[i].BgCount = item.GetInt("bg_count")
+		}
 
-					rootBucket.Aggs.ExtendedStats[key] = &esearch.ExtendStatsResult{
-						StatsResult: &esearch.StatsResult{
-							Count: v.GetInt("count"),
-							Max:   v.GetFloat64("max"),
-							Min:   v.GetFloat64("min"),
-							Sum:   v.GetFloat64("sum"),
-							Avg:   v.GetFloat64("avg"),
-						},
-						SumOfSquares:       v.GetFloat64("sum_of_squares"),
-						Variance:           v.GetFloat64("variance"),
-						StdDeviation:       v.GetFloat64("std_deviation"),
-						StdDeviationBounds: stdDeviationBounds,
-					}
-				case esearch.TopHits:
-					topHitsV := v.Get("hits")
+		if aggsResult.Significant == nil {
+			aggsResult.Significant = make(map[string]*esearch.SignificantResult)
+		}
+		aggsResult.Significant[key] = &esearch.SignificantResult{
+			DocCount: v.GetInt("doc_count"),
+			BgCount:  v.GetInt("bg_count"),
+			Buckets:  buckets,
+		}
+	case esearch.GeoHashGrid, esearch.GeoTileGrid:
+		bucketsArr := v.GetArray("buckets")
+		buckets := make([]esearch.GeoGridBucket, len(bucketsArr))
+		for i, item := range bucketsArr {
+			var errs []error
+			if bucketObj := item.GetObject(); bucketObj != nil {
+				buckets[i].Bucket, errs = subAggParser(bucketObj, dest)
+				errorSet = append(errorSet, errs...)
+			}
+			if lastString == esearch.GeoTileGrid {
+				buckets[i].Tile = geoTileParser(buckets[i].Key)
+			}
+		}
 
-					hitsArr := topHitsV.GetArray("hits")
-					hitsBuckets := make([]*esearch.HitsBucket, len(hitsArr))
-					for i, hitsV := range hitsArr {
+		if aggsResult.GeoGrid == nil {
+			aggsResult.GeoGrid = make(map[string]*esearch.GeoGridResult)
+		}
+		aggsResult.GeoGrid[key] = &esearch.GeoGridResult{
+			Buckets: buckets,
+		}
+	case esearch.GeoBounds:
+		boundsV := v.Get("bounds")
 
-						newDest, err := topHitsParser(hitsV, dest)
-						if err != nil {
-							errorSet = append(errorSet, err)
-						}
-						hitsBuckets[i] = &esearch.HitsBucket{
-							Source: newDest,
-						}
-					}
-					rootBucket.Aggs.TopHits = &esearch.HitsResult{
-						Total: topHitsV.GetInt("total"),
-						Hits:  hitsBuckets,
-					}
-				}
+		if aggsResult.GeoBounds == nil {
+			aggsResult.GeoBounds = make(map[string]*esearch.GeoBoundsResult)
+		}
+		aggsResult.GeoBounds[key] = &esearch.GeoBoundsResult{
+			TopLeft:     geoPointParser(boundsV.Get("top_left")),
+			BottomRight: geoPointParser(boundsV.Get("bottom_right")),
+		}
+	case esearch.GeoCentroid:
+		if aggsResult.GeoCentroid == nil {
+			aggsResult.GeoCentroid = make(map[string]*esearch.GeoCentroidResult)
+		}
+		aggsResult.GeoCentroid[key] = &esearch.GeoCentroidResult{
+			Location: geoPointParser(v.Get("location")),
+			Count:    v.GetInt("count"),
+		}
+	case esearch.Filters, esearch.AdjacencyMatrix:
+		var buckets []esearch.Bucket
+		buckets, errorSet = keyedBucketsParser(v.Get("buckets"), dest)
+
+		if aggsResult.Filters == nil {
+			aggsResult.Filters = make(map[string]*esearch.FiltersResult)
+		}
+		aggsResult.Filters[key] = &esearch.FiltersResult{
+			Buckets: buckets,
+		}
+	case esearch.Percentiles, esearch.PercentileRanks, esearch.PercentilesBucket:
+		if aggsResult.Percentiles == nil {
+			aggsResult.Percentiles = make(map[string]*esearch.PercentilesResult)
+		}
+		aggsResult.Percentiles[key] = percentilesParser(v.Get("values"))
+	case esearch.TopHits:
+		topHitsV := v.Get("hits")
+
+		hitsArr := topHitsV.GetArray("hits")
+		hitsBuckets := make([]*esearch.HitsBucket, len(hitsArr))
+		for i, hitsV := range hitsArr {
+			newDest, err := topHitsParser(hitsV, dest)
+			if err != nil {
+				errorSet = append(errorSet, err)
+			}
+
+			hitsBuckets[i] = &esearch.HitsBucket{
+				Source: newDest,
 			}
 		}
+		aggsResult.TopHits = &esearch.HitsResult{
+			Total: topHitsV.GetInt("total"),
+			Hits:  hitsBuckets,
+		}
+	}
+
+	return errorSet
+}
+
+// percentilesParser 解析 percentiles, percentile_ranks 的 values, keyed 为 true 时是对象, 为 false 时是数组
+func percentilesParser(valuesV *fastjson.Value) *esearch.PercentilesResult {
+	result := &esearch.PercentilesResult{
+		Values: make([]esearch.PercentileValue, 0),
+	}
+
+	if valuesV == nil {
+		return result
+	}
+
+	switch valuesV.Type() {
+	case fastjson.TypeArray:
+		for _, itemV := range valuesV.GetArray() {
+			result.Values = append(result.Values, esearch.PercentileValue{
+				Key:           itemV.GetFloat64("key"),
+				Value:         itemV.GetFloat64("value"),
+				ValueAsString: string(itemV.GetStringBytes("value_as_string")),
+			})
+		}
+	case fastjson.TypeObject:
+		valuesObj := valuesV.GetObject()
+		valuesObj.Visit(func(k []byte, v *fastjson.Value) {
+			key := string(k)
+			if strings.HasSuffix(key, "_as_string") {
+				return
+			}
+
+			percent, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				return
+			}
+
+			result.Values = append(result.Values, esearch.PercentileValue{
+				Key:           percent,
+				Value:         v.GetFloat64(),
+				ValueAsString: string(valuesObj.Get(key + "_as_string").GetStringBytes()),
+			})
+		})
+	}
+
+	return result
+}
+
+func bucketsParser(bucketsArr []*fastjson.Value, dest any) (buckets []esearch.Bucket, errorSet []error) {
+	buckets = make([]esearch.Bucket, len(bucketsArr))
+	for i, item := range bucketsArr {
+		bucketObj := item.GetObject()
+
+		var bucket esearch.Bucket
+		if bucketObj != nil {
+			var errs []error
+			bucket, errs = subAggParser(bucketObj, dest)
+			errorSet = append(errorSet, errs...)
+		}
+		buckets[i] = bucket
+	}
+
+	return buckets, errorSet
+}
+
+func geoPointParser(pointV *fastjson.Value) *esearch.GeoPoint {
+	if pointV == nil || pointV.Type() != fastjson.TypeObject {
+		return nil
+	}
+
+	return &esearch.GeoPoint{
+		Lat: pointV.GetFloat64("lat"),
+		Lon: pointV.GetFloat64("lon"),
+	}
+}
+
+// geoTileParser 解析 zoom/x/y 格式的 geotile
+func geoTileParser(key string) *esearch.GeoTile {
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 {
+		return nil
+	}
+
+	tile := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil
+		}
+		tile[i] = n
+	}
+
+	return &esearch.GeoTile{
+		Zoom: tile[0],
+		X:    tile[1],
+		Y:    tile[2],
+	}
+}
+
+// keyedBucketsParser 解析 keyed 为 true 时以桶名称为键的 buckets 对象, 桶中没有 key 时桶名称写入 Bucket.Key, buckets 为数组时与 bucketsParser 相同
+func keyedBucketsParser(bucketsV *fastjson.Value, dest any) (buckets []esearch.Bucket, errorSet []error) {
+	if bucketsV == nil {
+		return make([]esearch.Bucket, 0), nil
+	}
+
+	if bucketsV.Type() != fastjson.TypeObject {
+		return bucketsParser(bucketsV.GetArray(), dest)
+	}
+
+	buckets = make([]esearch.Bucket, 0)
+	bucketsV.GetObject().Visit(func(k []byte, v *fastjson.Value) {
+		var bucket esearch.Bucket
+		if bucketObj := v.GetObject(); bucketObj != nil {
+			var errs []error
+			bucket, errs = subAggParser(bucketObj, dest)
+			errorSet = append(errorSet, errs...)
+		}
+		if bucket.Key == "" {
+			bucket.Key = string(k)
+		}
+		buckets = append(buckets, bucket)
 	})
-	return
+
+	return buckets, errorSet
 }
 
 func topHitsParser(hitsV *fastjson.Value, dest any) (newDest any, err error) {
@@ -327,13 +469,13 @@ func topHitsParser(hitsV *fastjson.Value, dest any) (newDest any, err error) {
 				}
 				val, err = setFieldValue(field, sourceV.Get(name))
 				if err != nil {
-					return err, nil
+					return nil, err
 				}
 				newStruct.Field(i).Set(reflect.ValueOf(val))
 			}
 			newDest = newStruct.Interface()
 		default:
-			return errors.New("only supports parsing variables of the types map, *map, *struct"), nil
+			return nil, errors.New("only supports parsing variables of the types map, *map, *struct")
 		}
 	}
 	return
@@ -608,23 +750,36 @@ func GetBool(value *fastjson.Value) (val bool, err error) {
 
 // ConvertValue 将 fastjson.Value 转换为 Go 原生类型
 func ConvertValue(v *fastjson.Value) any {
+	return convertValue(v, false)
+}
+
+// ConvertRawValue 与 ConvertValue 相同, 但数字转换为 json.Number 保留原始文本, 超过 2^53 的整数不会丢失精度.
+// composite 聚合的 key 和 after_key 使用, 原样回传给 after 时与 es 返回的值完全一致
+func ConvertRawValue(v *fastjson.Value) any {
+	return convertValue(v, true)
+}
+
+func convertValue(v *fastjson.Value, rawNumber bool) any {
 	switch v.Type() {
 	case fastjson.TypeObject:
 		m := make(map[string]any)
 		v.GetObject().Visit(func(key []byte, val *fastjson.Value) {
-			m[string(key)] = ConvertValue(val)
+			m[string(key)] = convertValue(val, rawNumber)
 		})
 		return m
 	case fastjson.TypeArray:
 		arr := v.GetArray()
 		result := make([]any, len(arr))
 		for i, val := range arr {
-			result[i] = ConvertValue(val)
+			result[i] = convertValue(val, rawNumber)
 		}
 		return result
 	case fastjson.TypeString:
 		return string(v.GetStringBytes())
 	case fastjson.TypeNumber:
+		if rawNumber {
+			return json.Number(v.MarshalTo(nil))
+		}
 		return v.GetFloat64()
 	case fastjson.TypeTrue:
 		return true
@@ -0,0 +1,313 @@
+package parser
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"github.com/valyala/fastjson"
+)
+
+// parseAggs 解析 aggregations 响应, 解析出错时测试失败
+func parseAggs(t *testing.T, aggregations string) *esearch.AggsResult {
+	t.Helper()
+
+	aggsResult, errorSet := AggValueParser(fastjson.MustParse(aggregations).GetObject(), nil)
+	if len(errorSet) > 0 {
+		t.Fatalf("AggValueParser() errors = %v", errorSet)
+	}
+
+	return aggsResult
+}
+
+func TestAggValueParser(t *testing.T) {
+	tests := []struct {
+		name         string
+		aggregations string
+		dest         any
+		got          func(aggsResult *esearch.AggsResult) any
+		want         any
+		wantErrs     int
+	}{
+		{
+			name:         "sibling aggregations of the same type are all kept",
+			aggregations: `{"author_terms":{"buckets":[{"key":"kim","doc_count":1}]},"tags_terms":{"buckets":[{"key":"go","doc_count":2}]},"price_avg":{"value":1},"price_max":{"value":2}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				return []any{
+					aggsResult.Terms["author_terms"].Buckets[0].Key,
+					aggsResult.Terms["tags_terms"].Buckets[0].Key,
+					aggsResult.Arithmetic["price_avg"].Value,
+					aggsResult.Arithmetic["price_max"].Value,
+				}
+			},
+			want: []any{"kim", "go", float64(1), float64(2)},
+		},
+		{
+			name:         "sub aggregations of the same type are all kept",
+			aggregations: `{"author_terms":{"buckets":[{"key":"kim","doc_count":1,"price_avg":{"value":1},"price_sum":{"value":3}}]}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				arithmetic := aggsResult.Terms["author_terms"].Buckets[0].Aggs.Arithmetic
+				return []any{arithmetic["price_avg"].Value, arithmetic["price_sum"].Value}
+			},
+			want: []any{float64(1), float64(3)},
+		},
+		{
+			name:         "top_hits errors from every bucket are returned",
+			aggregations: `{"author_terms":{"buckets":[{"key":"kim","doc_count":1,"_topHits":{"hits":{"total":1,"hits":[{"_id":"1","_source":{}}]}}},{"key":"lee","doc_count":1,"_topHits":{"hits":{"total":1,"hits":[{"_id":"2","_source":{}}]}}}]}}`,
+			dest:         new(int),
+			got: func(aggsResult *esearch.AggsResult) any {
+				return len(aggsResult.Terms["author_terms"].Buckets)
+			},
+			want:     2,
+			wantErrs: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggsResult, errorSet := AggValueParser(fastjson.MustParse(tt.aggregations).GetObject(), tt.dest)
+			if len(errorSet) != tt.wantErrs {
+				t.Fatalf("AggValueParser() errors = %v, want %d errors", errorSet, tt.wantErrs)
+			}
+
+			got := tt.got(aggsResult)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricAggParser(t *testing.T) {
+	tests := []struct {
+		name         string
+		aggregations string
+		got          func(aggsResult *esearch.AggsResult) any
+		want         any
+	}{
+		{
+			name:         "percentiles keyed",
+			aggregations: `{"latency_percentiles":{"values":{"50.0":12.5,"50.0_as_string":"12.5ms","99.0":80}}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				return aggsResult.Percentiles["latency_percentiles"].Values
+			},
+			want: []esearch.PercentileValue{
+				{Key: 50, Value: 12.5, ValueAsString: "12.5ms"},
+				{Key: 99, Value: 80},
+			},
+		},
+		{
+			name:         "percentile_ranks array",
+			aggregations: `{"latency_percentileRanks":{"values":[{"key":100,"value":93.2}]}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				return aggsResult.Percentiles["latency_percentileRanks"].Values
+			},
+			want: []esearch.PercentileValue{{Key: 100, Value: 93.2}},
+		},
+		{
+			name:         "median_absolute_deviation",
+			aggregations: `{"latency_medianAbsoluteDeviation":{"value":2.5},"latency_avg":{"value":10}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				return []float64{
+					aggsResult.MedianAbsoluteDeviation["latency_medianAbsoluteDeviation"].Value,
+					aggsResult.Arithmetic["latency_avg"].Value,
+				}
+			},
+			want: []float64{2.5, 10},
+		},
+		{
+			name:         "max_bucket keys and stats_bucket",
+			aggregations: `{"peak_maxBucket":{"value":12,"keys":["2024-01-02","2024-01-05"]},"count_statsBucket":{"count":3,"min":1,"max":12,"avg":5,"sum":15}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				peak := aggsResult.Arithmetic["peak_maxBucket"]
+				return []any{peak.Value, peak.Keys, *aggsResult.Stats["count_statsBucket"]}
+			},
+			want: []any{
+				float64(12),
+				[]string{"2024-01-02", "2024-01-05"},
+				esearch.StatsResult{Count: 3, Min: 1, Max: 12, Avg: 5, Sum: 15},
+			},
+		},
+		{
+			name:         "percentiles_bucket",
+			aggregations: `{"price_percentilesBucket":{"values":{"50.0":7}}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				return aggsResult.Percentiles["price_percentilesBucket"].Values
+			},
+			want: []esearch.PercentileValue{{Key: 50, Value: 7}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.got(parseAggs(t, tt.aggregations))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBucketAggParser(t *testing.T) {
+	tests := []struct {
+		name         string
+		aggregations string
+		got          func(aggsResult *esearch.AggsResult) any
+		want         any
+	}{
+		{
+			name:         "composite after_key keeps large numbers",
+			aggregations: `{"page_composite":{"after_key":{"id":9007199254740993,"platform":"web"},"buckets":[{"key":{"id":1,"platform":"web"},"doc_count":2}]}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				composite := aggsResult.Composite["page_composite"]
+				return []any{composite.AfterKey, composite.Buckets[0].CompositeKey, composite.Buckets[0].DocCount}
+			},
+			want: []any{
+				map[string]any{"id": json.Number("9007199254740993"), "platform": "web"},
+				map[string]any{"id": json.Number("1"), "platform": "web"},
+				2,
+			},
+		},
+		{
+			name:         "nested descends into sub aggregations",
+			aggregations: `{"comments_nested":{"doc_count":5,"comments.author_terms":{"buckets":[{"key":"kim","doc_count":3,"root_reverseNested":{"doc_count":2}}]}}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				nested := aggsResult.SingleBucket["comments_nested"]
+				author := nested.Aggs.Terms["comments.author_terms"].Buckets[0]
+				return []any{nested.DocCount, author.Key, author.DocCount, author.Aggs.SingleBucket["root_reverseNested"].DocCount}
+			},
+			want: []any{5, "kim", 3, 2},
+		},
+		{
+			name:         "filters keyed buckets",
+			aggregations: `{"sentiment_filters":{"buckets":{"negative":{"doc_count":1},"positive":{"doc_count":4,"lang_terms":{"buckets":[{"key":"zh","doc_count":4}]}}}}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				buckets := aggsResult.Filters["sentiment_filters"].Buckets
+				return []any{buckets[0].Key, buckets[0].DocCount, buckets[1].Key, buckets[1].DocCount, buckets[1].Aggs.Terms["lang_terms"].Buckets[0].Key}
+			},
+			want: []any{"negative", 1, "positive", 4, "zh"},
+		},
+		{
+			name:         "adjacency_matrix array buckets",
+			aggregations: `{"co_adjacencyMatrix":{"buckets":[{"key":"a","doc_count":3},{"key":"a&b","doc_count":1}]}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				buckets := aggsResult.Filters["co_adjacencyMatrix"].Buckets
+				return []any{buckets[0].Key, buckets[1].Key, buckets[1].DocCount}
+			},
+			want: []any{"a", "a&b", 1},
+		},
+		{
+			name:         "significant_terms score and bg_count",
+			aggregations: `{"tags_significantTerms":{"doc_count":100,"bg_count":1000,"buckets":[{"key":"recall","doc_count":20,"score":0.8,"bg_count":30}]}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				significant := aggsResult.Significant["tags_significantTerms"]
+				bucket := significant.Buckets[0]
+				return []any{significant.DocCount, significant.BgCount, bucket.Key, bucket.DocCount, bucket.Score, bucket.BgCount}
+			},
+			want: []any{100, 1000, "recall", 20, 0.8, 30},
+		},
+		{
+			name:         "rare_terms buckets",
+			aggregations: `{"author_rareTerms":{"buckets":[{"key":"kim","doc_count":1}]}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				bucket := aggsResult.Terms["author_rareTerms"].Buckets[0]
+				return []any{bucket.Key, bucket.DocCount}
+			},
+			want: []any{"kim", 1},
+		},
+		{
+			name:         "multi_terms keys keep large numbers",
+			aggregations: `{"pair_multiTerms":{"buckets":[{"key":["web",2024],"key_as_string":"web|2024","doc_count":3},{"key":["app",9007199254740993],"doc_count":1}]}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				buckets := aggsResult.Terms["pair_multiTerms"].Buckets
+				return []any{buckets[0].Keys, buckets[0].KeyAsString, buckets[0].DocCount, buckets[1].Keys}
+			},
+			want: []any{[]any{"web", json.Number("2024")}, "web|2024", 3, []any{"app", json.Number("9007199254740993")}},
+		},
+		{
+			name:         "numeric terms key keeps its text",
+			aggregations: `{"user_id_terms":{"buckets":[{"key":9007199254740993,"doc_count":1},{"key":1.5,"doc_count":2}]}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				buckets := aggsResult.Terms["user_id_terms"].Buckets
+				return []string{buckets[0].Key, buckets[1].Key}
+			},
+			want: []string{"9007199254740993", "1.5"},
+		},
+		{
+			name:         "random_sampler doc_count and sub aggregations",
+			aggregations: `{"random_randomSampler":{"seed":42,"probability":0.1,"doc_count":120,"price_avg":{"value":3.5}}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				sampler := aggsResult.SingleBucket["random_randomSampler"]
+				return []any{sampler.DocCount, sampler.Aggs.Arithmetic["price_avg"].Value}
+			},
+			want: []any{120, 3.5},
+		},
+		{
+			name:         "geotile_grid tile and geohash_grid key",
+			aggregations: `{"location_geotileGrid":{"buckets":[{"key":"8/213/98","doc_count":4}]},"location_geohashGrid":{"buckets":[{"key":"wx4g","doc_count":2}]}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				tile := aggsResult.GeoGrid["location_geotileGrid"].Buckets[0]
+				hash := aggsResult.GeoGrid["location_geohashGrid"].Buckets[0]
+				return []any{tile.Key, tile.DocCount, *tile.Tile, hash.Key, hash.Tile}
+			},
+			want: []any{"8/213/98", 4, esearch.GeoTile{Zoom: 8, X: 213, Y: 98}, "wx4g", (*esearch.GeoTile)(nil)},
+		},
+		{
+			name:         "geo_bounds and geo_centroid",
+			aggregations: `{"location_geoBounds":{"bounds":{"top_left":{"lat":40,"lon":110},"bottom_right":{"lat":30,"lon":120}}},"location_geoCentroid":{"location":{"lat":35,"lon":115},"count":6}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				bounds := aggsResult.GeoBounds["location_geoBounds"]
+				centroid := aggsResult.GeoCentroid["location_geoCentroid"]
+				return []any{*bounds.TopLeft, *bounds.BottomRight, *centroid.Location, centroid.Count}
+			},
+			want: []any{esearch.GeoPoint{Lat: 40, Lon: 110}, esearch.GeoPoint{Lat: 30, Lon: 120}, esearch.GeoPoint{Lat: 35, Lon: 115}, 6},
+		},
+		{
+			name:         "geo_distance keyed buckets",
+			aggregations: `{"location_geoDistance":{"buckets":{"*-10.0":{"to":10,"doc_count":3},"far":{"from":10,"doc_count":5}}}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				buckets := aggsResult.Range["location_geoDistance"].Buckets
+				return []any{buckets[0].Key, buckets[0].To, buckets[0].DocCount, buckets[1].Key, buckets[1].From, buckets[1].DocCount}
+			},
+			want: []any{"*-10.0", float64(10), 3, "far", float64(10), 5},
+		},
+		{
+			name:         "auto_date_histogram interval",
+			aggregations: `{"date_autoDateHistogram":{"buckets":[{"key_as_string":"2024-01-01","key":1704067200000,"doc_count":7}],"interval":"7d"}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				histogram := aggsResult.Histogram["date_autoDateHistogram"]
+				bucket := histogram.Buckets[0]
+				return []any{histogram.Interval, bucket.Key, bucket.KeyAsString, bucket.DocCount}
+			},
+			want: []any{"7d", "1704067200000", "2024-01-01", 7},
+		},
+		{
+			name:         "date_histogram keyed buckets",
+			aggregations: `{"date_dateHistogram":{"buckets":{"2024-01":{"key_as_string":"2024-01","key":1704067200000,"doc_count":3}}}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				bucket := aggsResult.Histogram["date_dateHistogram"].Buckets[0]
+				return []any{bucket.Key, bucket.KeyAsString, bucket.DocCount}
+			},
+			want: []any{"1704067200000", "2024-01", 3},
+		},
+		{
+			name:         "variable_width_histogram min and max",
+			aggregations: `{"price_variableWidthHistogram":{"buckets":[{"min":1,"key":2.5,"max":4,"doc_count":2}]}}`,
+			got: func(aggsResult *esearch.AggsResult) any {
+				bucket := aggsResult.Histogram["price_variableWidthHistogram"].Buckets[0]
+				return []any{bucket.Min, bucket.Key, bucket.Max, bucket.DocCount}
+			},
+			want: []any{float64(1), "2.5", float64(4), 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.got(parseAggs(t, tt.aggregations))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
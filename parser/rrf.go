@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"github.com/valyala/fastjson"
+	"sort"
+)
+
+// RRF 客户端的倒数排序融合(Reciprocal Rank Fusion), 用于不支持 rrf 检索器的集群, 合并多次查询的 hits.hits,
+// 文档得分为 sum(1 / (rankConstant + rank)), 按照 _index 和 _id 判断是否为同一文档. rankConstant 小于等于 0 时默认为 60,
+// rankWindowSize 大于 0 时, 每个结果列表只取前 rankWindowSize 个文档参与融合. 返回的结果可以继续使用 HitsValueParser 解析
+func RRF(rankConstant int, rankWindowSize int, hitsLists ...[]*fastjson.Value) []*fastjson.Value {
+	if rankConstant <= 0 {
+		rankConstant = 60
+	}
+
+	type rankedHit struct {
+		hit   *fastjson.Value
+		score float64
+		order int
+	}
+
+	rankedHits := make(map[string]*rankedHit)
+	for _, hitsList := range hitsLists {
+		for i, hit := range hitsList {
+			if rankWindowSize > 0 && i >= rankWindowSize {
+				break
+			}
+
+			key := string(hit.GetStringBytes("_index")) + "/" + string(hit.GetStringBytes("_id"))
+			item, ok := rankedHits[key]
+			if !ok {
+				item = &rankedHit{hit: hit, order: len(rankedHits)}
+				rankedHits[key] = item
+			}
+			item.score += 1 / float64(rankConstant+i+1)
+		}
+	}
+
+	items := make([]*rankedHit, 0, len(rankedHits))
+	for _, item := range rankedHits {
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].score != items[j].score {
+			return items[i].score > items[j].score
+		}
+		return items[i].order < items[j].order
+	})
+
+	hits := make([]*fastjson.Value, len(items))
+	for i, item := range items {
+		hits[i] = item.hit
+	}
+
+	return hits
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/valyala/fastjson"
+)
+
+func TestRRF(t *testing.T) {
+	hits := func(s string) []*fastjson.Value {
+		return fastjson.MustParse(s).GetArray()
+	}
+
+	tests := []struct {
+		name           string
+		rankWindowSize int
+		hitsLists      [][]*fastjson.Value
+		want           []string
+	}{
+		{
+			name: "documents in both lists rank first",
+			hitsLists: [][]*fastjson.Value{
+				hits(`[{"_index":"a","_id":"1"},{"_index":"a","_id":"2"}]`),
+				hits(`[{"_index":"a","_id":"3"},{"_index":"a","_id":"2"}]`),
+			},
+			want: []string{"2", "1", "3"},
+		},
+		{
+			name:           "rank window size",
+			rankWindowSize: 1,
+			hitsLists: [][]*fastjson.Value{
+				hits(`[{"_index":"a","_id":"1"},{"_index":"a","_id":"2"}]`),
+				hits(`[{"_index":"a","_id":"3"},{"_index":"a","_id":"2"}]`),
+			},
+			want: []string{"1", "3"},
+		},
+		{
+			name: "same id in different index",
+			hitsLists: [][]*fastjson.Value{
+				hits(`[{"_index":"a","_id":"1"}]`),
+				hits(`[{"_index":"b","_id":"1"}]`),
+			},
+			want: []string{"1", "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, hit := range RRF(0, tt.rankWindowSize, tt.hitsLists...) {
+				got = append(got, string(hit.GetStringBytes("_id")))
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package elastic
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"github.com/KingSolvewer/elasticsearch-query-builder/specialized"
+)
+
+// WherePercolate Must percolate 查询, documents 为一个或多个待匹配的文档, 也可以是 specialized.PercolateRef 引用已索引的文档, PercolateRef 只能单独使用
+func WherePercolate(field string, documents ...any) *Builder {
+	return builder.WherePercolate(field, documents...)
+}
+
+// WherePercolate Must percolate 查询, documents 为一个或多个待匹配的文档, 也可以是 specialized.PercolateRef 引用已索引的文档, PercolateRef 只能单独使用
+func (b *Builder) WherePercolate(field string, documents ...any) *Builder {
+	b.percolate(esearch.Must, field, documents...)
+
+	return b
+}
+
+// WhereNotPercolate MustNot percolate 查询, documents 为一个或多个待匹配的文档, 也可以是 specialized.PercolateRef 引用已索引的文档, PercolateRef 只能单独使用
+func WhereNotPercolate(field string, documents ...any) *Builder {
+	return builder.WhereNotPercolate(field, documents...)
+}
+
+// WhereNotPercolate MustNot percolate 查询, documents 为一个或多个待匹配的文档, 也可以是 specialized.PercolateRef 引用已索引的文档, PercolateRef 只能单独使用
+func (b *Builder) WhereNotPercolate(field string, documents ...any) *Builder {
+	b.percolate(esearch.MustNot, field, documents...)
+
+	return b
+}
+
+// OrWherePercolate Should percolate 查询, documents 为一个或多个待匹配的文档, 也可以是 specialized.PercolateRef 引用已索引的文档, PercolateRef 只能单独使用
+func OrWherePercolate(field string, documents ...any) *Builder {
+	return builder.OrWherePercolate(field, documents...)
+}
+
+// OrWherePercolate Should percolate 查询, documents 为一个或多个待匹配的文档, 也可以是 specialized.PercolateRef 引用已索引的文档, PercolateRef 只能单独使用
+func (b *Builder) OrWherePercolate(field string, documents ...any) *Builder {
+	b.percolate(esearch.Should, field, documents...)
+
+	return b
+}
+
+// FilterPercolate Filter percolate 查询, documents 为一个或多个待匹配的文档, 也可以是 specialized.PercolateRef 引用已索引的文档, PercolateRef 只能单独使用
+func FilterPercolate(field string, documents ...any) *Builder {
+	return builder.FilterPercolate(field, documents...)
+}
+
+// FilterPercolate Filter percolate 查询, documents 为一个或多个待匹配的文档, 也可以是 specialized.PercolateRef 引用已索引的文档, PercolateRef 只能单独使用
+func (b *Builder) FilterPercolate(field string, documents ...any) *Builder {
+	b.percolate(esearch.FilterClause, field, documents...)
+
+	return b
+}
+
+// PercolatorDocument 生成存储到 percolator 类型字段的文档, 查询语句与 Dsl() 生成的 query 完全相同
+func (b *Builder) PercolatorDocument(field string) (map[string]any, error) {
+	document := make(map[string]any)
+
+	if b.raw != "" {
+		body := make(map[string]json.RawMessage)
+		err := json.Unmarshal([]byte(b.raw), &body)
+		if err != nil {
+			return nil, err
+		}
+
+		query, ok := body["query"]
+		if !ok {
+			return nil, errors.New("query is not existing in raw dsl")
+		}
+
+		document[field] = query
+		return document, nil
+	}
+
+	query := b.compile()
+	if len(query.Query) == 0 {
+		return nil, errors.New("query is empty, knn and retriever can not be stored in percolator")
+	}
+
+	document[field] = query.Query
+	return document, nil
+}
+
+func (b *Builder) percolate(clauseTyp esearch.BoolClauseType, field string, documents ...any) {
+	if field == "" || len(documents) == 0 {
+		return
+	}
+
+	percolate := specialized.Percolate{Field: field}
+
+	if len(documents) == 1 {
+		switch document := documents[0].(type) {
+		case specialized.PercolateRef:
+			percolate.PercolateRef = &document
+		case *specialized.PercolateRef:
+			percolate.PercolateRef = document
+		default:
+			percolate.Document = document
+		}
+	} else {
+		for _, document := range documents {
+			switch document.(type) {
+			case specialized.PercolateRef, *specialized.PercolateRef:
+				panic("Percolate setting is fault! PercolateRef can not be mixed with documents")
+			}
+		}
+
+		percolate.Documents = documents
+	}
+
+	b.append(clauseTyp, specialized.PercolateQuery{Percolate: percolate})
+}
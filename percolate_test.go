@@ -0,0 +1,92 @@
+package elastic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/specialized"
+)
+
+func TestPercolate(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "single document",
+			b:    NewBuilder().WherePercolate("query", map[string]any{"title": "go"}),
+			want: `{"query":{"bool":{"must":[{"percolate":{"field":"query","document":{"title":"go"}}}]}}}`,
+		},
+		{
+			name: "multiple documents",
+			b:    NewBuilder().FilterPercolate("query", map[string]any{"title": "go"}, map[string]any{"title": "es"}),
+			want: `{"query":{"bool":{"filter":[{"percolate":{"field":"query","documents":[{"title":"go"},{"title":"es"}]}}]}}}`,
+		},
+		{
+			name: "indexed document reference",
+			b:    NewBuilder().OrWherePercolate("query", specialized.PercolateRef{Index: "docs", Id: "1", Routing: "r"}),
+			want: `{"query":{"bool":{"should":[{"percolate":{"field":"query","index":"docs","id":"1","routing":"r"}}]}}}`,
+		},
+		{
+			name: "empty documents is skipped",
+			b:    NewBuilder().WhereNotPercolate("query"),
+			want: `{"query":{"match_all":{}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestPercolateRefMixedPanics(t *testing.T) {
+	assertPanic(t, func() {
+		NewBuilder().WherePercolate("query", map[string]any{"title": "go"}, &specialized.PercolateRef{Index: "docs", Id: "1"})
+	})
+}
+
+func TestPercolatorDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       *Builder
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "compiled query",
+			b:    NewBuilder().Where("a", 1),
+			want: `{"query":{"bool":{"must":[{"term":{"a":1}}]}}}`,
+		},
+		{
+			name: "raw dsl",
+			b:    NewBuilder().Raw(`{"query":{"term":{"a":1}},"size":1}`),
+			want: `{"query":{"term":{"a":1}}}`,
+		},
+		{
+			name:    "knn only",
+			b:       NewBuilder().Knn("vec", []float32{0.1}, 1, 1, nil),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := tt.b.PercolatorDocument("query")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PercolatorDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, err := json.Marshal(document)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertJson(t, string(got), tt.want)
+		})
+	}
+}
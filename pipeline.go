@@ -0,0 +1,234 @@
+package elastic
+
+import (
+	"fmt"
+	"github.com/KingSolvewer/elasticsearch-query-builder/aggs"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"strings"
+)
+
+// BucketsPath 校验 path 引用的聚合是否已经注册到当前 Builder 中, 用于提前检查路径.
+// 管道聚合的 buckets_path 在 Marshal 时统一校验, 引用不存在的聚合时 Marshal 返回错误.
+// 父管道聚合(derivative, cumulative_sum 等)在多桶聚合的子聚合闭包函数中使用, 引用同一父聚合下注册的聚合
+func (b *Builder) BucketsPath(path string) (aggs.BucketsPath, error) {
+	if err := b.checkBucketsPath(path); err != nil {
+		return "", err
+	}
+
+	return aggs.BucketsPath(path), nil
+}
+
+// Derivative 计算父聚合(histogram, date_histogram)中相邻桶的差值
+func (b *Builder) Derivative(name string, bucketsPath aggs.BucketsPath, param aggs.DerivativeParam) *Builder {
+	derivativeAggs := &aggs.DerivativeAggs{
+		Derivative: aggs.Derivative{
+			BucketsPath:     bucketsPath,
+			DerivativeParam: param,
+		},
+	}
+
+	return b.Aggs(name+esearch.Derivative, derivativeAggs)
+}
+
+func (b *Builder) CumulativeSum(name string, bucketsPath aggs.BucketsPath, format string) *Builder {
+	cumulativeSumAggs := &aggs.CumulativeSumAggs{
+		CumulativeSum: aggs.CumulativeSum{
+			BucketsPath: bucketsPath,
+			Format:      format,
+		},
+	}
+
+	return b.Aggs(name+esearch.CumulativeSum, cumulativeSumAggs)
+}
+
+func (b *Builder) MovingFn(name string, bucketsPath aggs.BucketsPath, window int, script string, param aggs.MovingFnParam) *Builder {
+	if window <= 0 || script == "" {
+		panic("MovingFn Aggregation setting is fault!")
+	}
+
+	movingFnAggs := &aggs.MovingFnAggs{
+		MovingFn: aggs.MovingFn{
+			BucketsPath:   bucketsPath,
+			Window:        window,
+			Script:        script,
+			MovingFnParam: param,
+		},
+	}
+
+	return b.Aggs(name+esearch.MovingFn, movingFnAggs)
+}
+
+func (b *Builder) SerialDiff(name string, bucketsPath aggs.BucketsPath, lag int, param aggs.PipelineParam) *Builder {
+	serialDiffAggs := &aggs.SerialDiffAggs{
+		SerialDiff: aggs.SerialDiff{
+			BucketsPath:   bucketsPath,
+			Lag:           lag,
+			PipelineParam: param,
+		},
+	}
+
+	return b.Aggs(name+esearch.SerialDiff, serialDiffAggs)
+}
+
+func (b *Builder) AvgBucket(name string, bucketsPath aggs.BucketsPath, param aggs.PipelineParam) *Builder {
+	return b.Aggs(name+esearch.AvgBucket, &aggs.AvgBucketAggs{BucketMetric: bucketMetric(bucketsPath, param)})
+}
+
+func (b *Builder) SumBucket(name string, bucketsPath aggs.BucketsPath, param aggs.PipelineParam) *Builder {
+	return b.Aggs(name+esearch.SumBucket, &aggs.SumBucketAggs{BucketMetric: bucketMetric(bucketsPath, param)})
+}
+
+func (b *Builder) MaxBucket(name string, bucketsPath aggs.BucketsPath, param aggs.PipelineParam) *Builder {
+	return b.Aggs(name+esearch.MaxBucket, &aggs.MaxBucketAggs{BucketMetric: bucketMetric(bucketsPath, param)})
+}
+
+func (b *Builder) MinBucket(name string, bucketsPath aggs.BucketsPath, param aggs.PipelineParam) *Builder {
+	return b.Aggs(name+esearch.MinBucket, &aggs.MinBucketAggs{BucketMetric: bucketMetric(bucketsPath, param)})
+}
+
+func (b *Builder) StatsBucket(name string, bucketsPath aggs.BucketsPath, param aggs.PipelineParam) *Builder {
+	return b.Aggs(name+esearch.StatsBucket, &aggs.StatsBucketAggs{BucketMetric: bucketMetric(bucketsPath, param)})
+}
+
+func (b *Builder) PercentilesBucket(name string, bucketsPath aggs.BucketsPath, percents []float64, param aggs.PipelineParam) *Builder {
+	percentilesBucketAggs := &aggs.PercentilesBucketAggs{
+		PercentilesBucket: aggs.PercentilesBucket{
+			BucketsPath:   bucketsPath,
+			Percents:      percents,
+			PipelineParam: param,
+		},
+	}
+
+	return b.Aggs(name+esearch.PercentilesBucket, percentilesBucketAggs)
+}
+
+// BucketScript bucketsPath 的键为脚本中的变量名, 例如 script.Source 为 params.negative / params.total
+func (b *Builder) BucketScript(name string, bucketsPath map[string]aggs.BucketsPath, script esearch.Script, param aggs.PipelineParam) *Builder {
+	if len(bucketsPath) == 0 {
+		panic("BucketScript Aggregation setting is fault!")
+	}
+
+	bucketScriptAggs := &aggs.BucketScriptAggs{
+		BucketScript: aggs.BucketScript{
+			BucketsPath:   bucketsPath,
+			Script:        script,
+			PipelineParam: param,
+		},
+	}
+
+	return b.Aggs(name+esearch.BucketScript, bucketScriptAggs)
+}
+
+func (b *Builder) BucketSelector(name string, bucketsPath map[string]aggs.BucketsPath, script esearch.Script, gapPolicy aggs.GapPolicy) *Builder {
+	if len(bucketsPath) == 0 {
+		panic("BucketSelector Aggregation setting is fault!")
+	}
+
+	bucketSelectorAggs := &aggs.BucketSelectorAggs{
+		BucketSelector: aggs.BucketSelector{
+			BucketsPath: bucketsPath,
+			Script:      script,
+			GapPolicy:   gapPolicy,
+		},
+	}
+
+	return b.Aggs(name+esearch.BucketSelector, bucketSelectorAggs)
+}
+
+// BucketSort 对父聚合的桶排序和分页, sort 的键为 buckets_path, 为空时只分页
+func (b *Builder) BucketSort(name string, sort []esearch.Sort, param aggs.BucketSortParam) *Builder {
+	sorts := make([]esearch.Sorter, 0, len(sort))
+	for _, item := range sort {
+		sorts = append(sorts, item)
+	}
+
+	bucketSortAggs := &aggs.BucketSortAggs{
+		BucketSort: aggs.BucketSort{
+			Sort:            sorts,
+			BucketSortParam: param,
+		},
+	}
+
+	return b.Aggs(name+esearch.BucketSort, bucketSortAggs)
+}
+
+func bucketMetric(bucketsPath aggs.BucketsPath, param aggs.PipelineParam) aggs.BucketMetric {
+	return aggs.BucketMetric{
+		BucketsPath:   bucketsPath,
+		PipelineParam: param,
+	}
+}
+
+// checkPipelines 校验全部管道聚合的 buckets_path, 在 Marshal 时执行. 同一父聚合的多个子聚合闭包函数注册的聚合合并后再校验,
+// 因此可以引用其他闭包函数中注册的兄弟聚合
+func (b *Builder) checkPipelines() error {
+	for _, aggregation := range b.aggregations {
+		if pipeline, ok := aggregation.Params.(aggs.Pipeline); ok {
+			for _, path := range pipeline.BucketsPaths() {
+				if err := b.checkBucketsPath(string(path)); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(aggregation.SubAggs) > 0 {
+			if err := aggregation.subBuilder().checkPipelines(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkBucketsPath 按照 > 逐级查找聚合, 最后一级可以是 _count, _key, 或者带有指标名称, 例如 price_stats.avg
+func (b *Builder) checkBucketsPath(path string) error {
+	if path == "" {
+		return fmt.Errorf("buckets_path is empty")
+	}
+
+	elements := strings.Split(path, ">")
+	current := b
+	for i, element := range elements {
+		last := i == len(elements)-1
+		if last && (element == "_count" || element == "_key") {
+			return nil
+		}
+
+		name := element
+		if index := strings.Index(name, "["); index > -1 {
+			name = name[:index]
+		}
+
+		aggregation, ok := current.aggregations[name]
+		if !ok && last {
+			if index := strings.LastIndex(name, "."); index > -1 {
+				aggregation, ok = current.aggregations[name[:index]]
+			}
+		}
+
+		if !ok {
+			return fmt.Errorf("buckets_path %s: aggregation %s is not existing", path, name)
+		}
+
+		if !last {
+			current = aggregation.subBuilder()
+		}
+	}
+
+	return nil
+}
+
+// subBuilder 返回注册了子聚合的 Builder, 第一次调用时执行子聚合的闭包函数, 之后复用
+func (aggregation *Aggregation) subBuilder() *Builder {
+	if aggregation.subAggBuilder == nil {
+		aggregation.subAggBuilder = NewBuilder()
+		for _, subAggFunc := range aggregation.SubAggs {
+			if subAggFunc != nil {
+				subAggFunc(aggregation.subAggBuilder)
+			}
+		}
+	}
+
+	return aggregation.subAggBuilder
+}
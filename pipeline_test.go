@@ -0,0 +1,157 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/aggs"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+func TestPipeline(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "parent pipelines reference siblings registered in another sub agg func",
+			b: NewBuilder().Size(0).DateGroupBy("date", aggs.HistogramParam{Interval: "1d"}, func(b *Builder) {
+				b.AggsFilter("negative", func(b *Builder) { b.Where("sentiment", -1) })
+			}, func(b *Builder) {
+				b.BucketScript("share", map[string]aggs.BucketsPath{"negative": "negative_filter>_count", "total": "_count"},
+					esearch.Script{Source: "params.negative / params.total"}, aggs.PipelineParam{})
+				b.Derivative("delta", "_count", aggs.DerivativeParam{})
+				b.BucketSort("top", []esearch.Sort{{"share_bucketScript": {Order: esearch.Desc}}}, aggs.BucketSortParam{Size: 3})
+			}).MaxBucket("peak", "date_dateHistogram>_count", aggs.PipelineParam{}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"date_dateHistogram":{"date_histogram":{"field":"date","interval":"1d"},"aggs":{"delta_derivative":{"derivative":{"buckets_path":"_count"}},"negative_filter":{"filter":{"bool":{"must":[{"term":{"sentiment":-1}}]}}},"share_bucketScript":{"bucket_script":{"buckets_path":{"negative":"negative_filter>_count","total":"_count"},"script":{"source":"params.negative / params.total"}}},"top_bucketSort":{"bucket_sort":{"sort":[{"share_bucketScript":{"order":"desc"}}],"size":3}}}},"peak_maxBucket":{"max_bucket":{"buckets_path":"date_dateHistogram>_count"}}}}`,
+		},
+		{
+			name: "sibling pipeline with metric name",
+			b: NewBuilder().Size(0).GroupBy("author", aggs.TermsParam{}, func(b *Builder) {
+				b.Stats("price", aggs.MetricParam{})
+			}).PercentilesBucket("price", "author_terms>price_stats.avg", []float64{50}, aggs.PipelineParam{GapPolicy: aggs.Skip}),
+			want: `{"size":0,"query":{"match_all":{}},"aggs":{"author_terms":{"terms":{"field":"author"},"aggs":{"price_stats":{"stats":{"field":"price"}}}},"price_percentilesBucket":{"percentiles_bucket":{"buckets_path":"author_terms>price_stats.avg","percents":[50],"gap_policy":"skip"}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestPipelineBucketsPathError(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+	}{
+		{
+			name: "sibling references missing aggregation",
+			b:    NewBuilder().Size(0).MaxBucket("peak", "date_dateHistogram>_count", aggs.PipelineParam{}),
+		},
+		{
+			name: "parent references missing sibling",
+			b: NewBuilder().Size(0).GroupBy("a", aggs.TermsParam{}, func(b *Builder) {
+				b.Derivative("d", "missing_avg", aggs.DerivativeParam{})
+			}),
+		},
+		{
+			name: "bucket_script references aggregation at wrong level",
+			b: NewBuilder().Size(0).Avg("price", aggs.MetricParam{}).GroupBy("a", aggs.TermsParam{}, func(b *Builder) {
+				b.BucketScript("s", map[string]aggs.BucketsPath{"p": "price_avg"}, esearch.Script{Source: "params.p"}, aggs.PipelineParam{})
+			}),
+		},
+		{
+			name: "empty buckets_path",
+			b:    NewBuilder().Size(0).SumBucket("total", "", aggs.PipelineParam{}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.b.Marshal(); err == nil {
+				t.Errorf("Marshal() expected buckets_path error")
+			}
+		})
+	}
+}
+
+func TestBucketsPath(t *testing.T) {
+	b := NewBuilder().GroupBy("author", aggs.TermsParam{}, func(b *Builder) {
+		b.Avg("price", aggs.MetricParam{})
+	})
+
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{path: "author_terms>price_avg"},
+		{path: "author_terms>_count"},
+		{path: "author_terms>price_max", wantErr: true},
+		{path: "price_avg", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := b.BucketsPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BucketsPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(path) != tt.path {
+				t.Errorf("BucketsPath() = %s, want %s", path, tt.path)
+			}
+		})
+	}
+}
+
+func TestSubAggFuncRunsOnce(t *testing.T) {
+	tests := []struct {
+		name string
+		b    func(fn SubAggFunc) *Builder
+	}{
+		{
+			name: "without pipeline",
+			b: func(fn SubAggFunc) *Builder {
+				return NewBuilder().GroupBy("author", aggs.TermsParam{}, fn)
+			},
+		},
+		{
+			name: "with nested pipeline",
+			b: func(fn SubAggFunc) *Builder {
+				return NewBuilder().GroupBy("author", aggs.TermsParam{}, func(b *Builder) {
+					b.DateGroupBy("date", aggs.HistogramParam{Interval: "day"}, fn, func(b *Builder) {
+						b.Derivative("delta", "price_avg", aggs.DerivativeParam{})
+					})
+				}).MaxBucket("peak", "author_terms>_count", aggs.PipelineParam{})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := 0
+			b := tt.b(func(b *Builder) {
+				count++
+				b.Avg("price", aggs.MetricParam{})
+			})
+
+			for i := 0; i < 3; i++ {
+				if _, err := b.Marshal(); err != nil {
+					t.Fatalf("Marshal() error = %v", err)
+				}
+			}
+
+			if count != 1 {
+				t.Errorf("sub agg func runs %d times, want 1", count)
+			}
+		})
+	}
+}
+
+func TestDslReturnsEmptyOnError(t *testing.T) {
+	b := NewBuilder().Size(0).MaxBucket("peak", "missing_terms>_count", aggs.PipelineParam{})
+	if dsl := b.Dsl(); dsl != "" {
+		t.Errorf("Dsl() = %s, want empty string", dsl)
+	}
+}
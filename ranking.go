@@ -0,0 +1,165 @@
+package elastic
+
+import (
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"github.com/KingSolvewer/elasticsearch-query-builder/specialized"
+)
+
+// WhereRankFeature Must rank_feature 查询, 文档必须包含该特征字段, 特征值参与计算得分
+func WhereRankFeature(field string, fn specialized.RankFeatureParamFunc) *Builder {
+	return builder.WhereRankFeature(field, fn)
+}
+
+// WhereRankFeature Must rank_feature 查询, 文档必须包含该特征字段, 特征值参与计算得分
+func (b *Builder) WhereRankFeature(field string, fn specialized.RankFeatureParamFunc) *Builder {
+	b.rankFeature(esearch.Must, field, fn)
+
+	return b
+}
+
+// WhereNotRankFeature MustNot rank_feature 查询, 排除包含该特征字段的文档, 不参与计算得分
+func WhereNotRankFeature(field string, fn specialized.RankFeatureParamFunc) *Builder {
+	return builder.WhereNotRankFeature(field, fn)
+}
+
+// WhereNotRankFeature MustNot rank_feature 查询, 排除包含该特征字段的文档, 不参与计算得分
+func (b *Builder) WhereNotRankFeature(field string, fn specialized.RankFeatureParamFunc) *Builder {
+	b.rankFeature(esearch.MustNot, field, fn)
+
+	return b
+}
+
+// OrWhereRankFeature Should rank_feature 查询, 特征值累加到文档得分中, 是 rank_feature 最常用的方式
+func OrWhereRankFeature(field string, fn specialized.RankFeatureParamFunc) *Builder {
+	return builder.OrWhereRankFeature(field, fn)
+}
+
+// OrWhereRankFeature Should rank_feature 查询, 特征值累加到文档得分中, 是 rank_feature 最常用的方式
+func (b *Builder) OrWhereRankFeature(field string, fn specialized.RankFeatureParamFunc) *Builder {
+	b.rankFeature(esearch.Should, field, fn)
+
+	return b
+}
+
+// FilterRankFeature Filter rank_feature 查询, 只保留包含该特征字段的文档, 不参与计算得分
+func FilterRankFeature(field string, fn specialized.RankFeatureParamFunc) *Builder {
+	return builder.FilterRankFeature(field, fn)
+}
+
+// FilterRankFeature Filter rank_feature 查询, 只保留包含该特征字段的文档, 不参与计算得分
+func (b *Builder) FilterRankFeature(field string, fn specialized.RankFeatureParamFunc) *Builder {
+	b.rankFeature(esearch.FilterClause, field, fn)
+
+	return b
+}
+
+// WhereDistanceFeature Must distance_feature 查询, origin 为日期或者 geo.Point, 文档必须包含该字段, 距离 origin 越近得分越高
+func WhereDistanceFeature(field string, origin any, pivot string, boost float32) *Builder {
+	return builder.WhereDistanceFeature(field, origin, pivot, boost)
+}
+
+// WhereDistanceFeature Must distance_feature 查询, origin 为日期或者 geo.Point, 文档必须包含该字段, 距离 origin 越近得分越高
+func (b *Builder) WhereDistanceFeature(field string, origin any, pivot string, boost float32) *Builder {
+	b.distanceFeature(esearch.Must, field, origin, pivot, boost)
+
+	return b
+}
+
+// WhereNotDistanceFeature MustNot distance_feature 查询, origin 为日期或者 geo.Point, 排除包含该字段的文档, 不参与计算得分
+func WhereNotDistanceFeature(field string, origin any, pivot string, boost float32) *Builder {
+	return builder.WhereNotDistanceFeature(field, origin, pivot, boost)
+}
+
+// WhereNotDistanceFeature MustNot distance_feature 查询, origin 为日期或者 geo.Point, 排除包含该字段的文档, 不参与计算得分
+func (b *Builder) WhereNotDistanceFeature(field string, origin any, pivot string, boost float32) *Builder {
+	b.distanceFeature(esearch.MustNot, field, origin, pivot, boost)
+
+	return b
+}
+
+// OrWhereDistanceFeature Should distance_feature 查询, origin 为日期或者 geo.Point, 距离 origin 越近累加的得分越高, 是 distance_feature 最常用的方式
+func OrWhereDistanceFeature(field string, origin any, pivot string, boost float32) *Builder {
+	return builder.OrWhereDistanceFeature(field, origin, pivot, boost)
+}
+
+// OrWhereDistanceFeature Should distance_feature 查询, origin 为日期或者 geo.Point, 距离 origin 越近累加的得分越高, 是 distance_feature 最常用的方式
+func (b *Builder) OrWhereDistanceFeature(field string, origin any, pivot string, boost float32) *Builder {
+	b.distanceFeature(esearch.Should, field, origin, pivot, boost)
+
+	return b
+}
+
+// FilterDistanceFeature Filter distance_feature 查询, origin 为日期或者 geo.Point, 只保留包含该字段的文档, 不参与计算得分
+func FilterDistanceFeature(field string, origin any, pivot string, boost float32) *Builder {
+	return builder.FilterDistanceFeature(field, origin, pivot, boost)
+}
+
+// FilterDistanceFeature Filter distance_feature 查询, origin 为日期或者 geo.Point, 只保留包含该字段的文档, 不参与计算得分
+func (b *Builder) FilterDistanceFeature(field string, origin any, pivot string, boost float32) *Builder {
+	b.distanceFeature(esearch.FilterClause, field, origin, pivot, boost)
+
+	return b
+}
+
+// Pinned pinned 查询, 将 ids 或 docs 指定的文档置顶, ids 和 docs 必须且只能设置一个, Builder 生成的查询作为 organic 查询
+func Pinned(ids []string, docs ...specialized.PinnedDoc) *Builder {
+	return builder.Pinned(ids, docs...)
+}
+
+// Pinned pinned 查询, 将 ids 或 docs 指定的文档置顶, ids 和 docs 必须且只能设置一个, Builder 生成的查询作为 organic 查询
+func (b *Builder) Pinned(ids []string, docs ...specialized.PinnedDoc) *Builder {
+	if (len(ids) == 0) == (len(docs) == 0) {
+		panic("Pinned setting is fault! one of ids and docs must be set")
+	}
+
+	b.pinned = &specialized.Pinned{
+		Ids:  ids,
+		Docs: docs,
+	}
+
+	return b
+}
+
+func (b *Builder) rankFeature(clauseTyp esearch.BoolClauseType, field string, fn specialized.RankFeatureParamFunc) {
+	if field == "" {
+		return
+	}
+
+	rankFeature := specialized.RankFeature{Field: field}
+	if fn != nil {
+		rankFeature.RankFeatureParam = fn()
+	}
+
+	b.append(clauseTyp, specialized.RankFeatureQuery{RankFeature: rankFeature})
+}
+
+func (b *Builder) distanceFeature(clauseTyp esearch.BoolClauseType, field string, origin any, pivot string, boost float32) {
+	if field == "" || origin == nil || pivot == "" {
+		return
+	}
+
+	b.append(clauseTyp, specialized.DistanceFeatureQuery{
+		DistanceFeature: specialized.DistanceFeature{
+			Field:  field,
+			Origin: origin,
+			Pivot:  pivot,
+			Boost:  boost,
+		},
+	})
+}
+
+// pinnedQuery 使用 pinned 包装查询语句
+func (b *Builder) pinnedQuery(query esearch.Query) esearch.Query {
+	if b.pinned == nil {
+		return query
+	}
+
+	newQuery := make(esearch.Query)
+	newQuery["pinned"] = &specialized.Pinned{
+		Ids:     b.pinned.Ids,
+		Docs:    b.pinned.Docs,
+		Organic: query,
+	}
+
+	return newQuery
+}
@@ -0,0 +1,85 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/specialized"
+)
+
+func TestRanking(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "rank_feature default",
+			b:    NewBuilder().OrWhereRankFeature("pr", nil),
+			want: `{"query":{"bool":{"should":[{"rank_feature":{"field":"pr"}}]}}}`,
+		},
+		{
+			name: "rank_feature log alongside must",
+			b: NewBuilder().Where("a", 1).OrWhereRankFeature("pr", func() specialized.RankFeatureParam {
+				return specialized.RankFeatureParam{Log: &specialized.Log{ScalingFactor: 4}}
+			}),
+			want: `{"query":{"bool":{"must":[{"term":{"a":1}}],"should":[{"rank_feature":{"field":"pr","log":{"scaling_factor":4}}}]}}}`,
+		},
+		{
+			name: "rank_feature sigmoid with boost",
+			b: NewBuilder().WhereRankFeature("pr", func() specialized.RankFeatureParam {
+				return specialized.RankFeatureParam{Sigmoid: &specialized.Sigmoid{Pivot: 7, Exponent: 0.6}, Boost: 2}
+			}),
+			want: `{"query":{"bool":{"must":[{"rank_feature":{"field":"pr","sigmoid":{"pivot":7,"exponent":0.6},"boost":2}}]}}}`,
+		},
+		{
+			name: "distance_feature date",
+			b:    NewBuilder().OrWhereDistanceFeature("date", "now", "7d", 2),
+			want: `{"query":{"bool":{"should":[{"distance_feature":{"field":"date","origin":"now","pivot":"7d","boost":2}}]}}}`,
+		},
+		{
+			name: "distance_feature geo point",
+			b:    NewBuilder().FilterDistanceFeature("location", []float64{-71.3, 41.15}, "1000m", 0),
+			want: `{"query":{"bool":{"filter":[{"distance_feature":{"field":"location","origin":[-71.3,41.15],"pivot":"1000m"}}]}}}`,
+		},
+		{
+			name: "distance_feature without pivot is skipped",
+			b:    NewBuilder().WhereDistanceFeature("date", "now", "", 0),
+			want: `{"query":{"match_all":{}}}`,
+		},
+		{
+			name: "pinned ids",
+			b:    NewBuilder().Where("a", 1).Pinned([]string{"1", "2"}),
+			want: `{"query":{"pinned":{"ids":["1","2"],"organic":{"bool":{"must":[{"term":{"a":1}}]}}}}}`,
+		},
+		{
+			name: "pinned docs with match_all organic",
+			b:    NewBuilder().Pinned(nil, specialized.PinnedDoc{Index: "i", Id: "1"}),
+			want: `{"query":{"pinned":{"docs":[{"_index":"i","_id":"1"}],"organic":{"match_all":{}}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestPinnedPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []string
+		docs []specialized.PinnedDoc
+	}{
+		{name: "neither ids nor docs"},
+		{name: "both ids and docs", ids: []string{"1"}, docs: []specialized.PinnedDoc{{Id: "2"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPanic(t, func() {
+				NewBuilder().Pinned(tt.ids, tt.docs...)
+			})
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package elastic
+
+import (
+	"errors"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"github.com/KingSolvewer/elasticsearch-query-builder/retriever"
+)
+
+type rrf struct {
+	param      retriever.RRFParam
+	retrievers []esearch.Retriever
+}
+
+// Retriever 设置检索器, 设置后不再生成 query 和 knn, 可以使用 StandardRetriever, KnnRetriever 组合检索器
+func Retriever(r esearch.Retriever) *Builder {
+	return builder.Retriever(r)
+}
+
+// Retriever 设置检索器, 设置后不再生成 query 和 knn, 可以使用 StandardRetriever, KnnRetriever 组合检索器
+func (b *Builder) Retriever(r esearch.Retriever) *Builder {
+	b.retriever = r
+
+	return b
+}
+
+// RRF 使用 rrf 检索器融合 Builder 的查询条件(standard 检索器), knn 搜索(knn 检索器)以及 retrievers 的结果
+func RRF(rankConstant int, rankWindowSize int, retrievers ...esearch.Retriever) *Builder {
+	return builder.RRF(rankConstant, rankWindowSize, retrievers...)
+}
+
+// RRF 使用 rrf 检索器融合 Builder 的查询条件(standard 检索器), knn 搜索(knn 检索器)以及 retrievers 的结果, 合计少于 2 个检索器时 Marshal 返回错误
+func (b *Builder) RRF(rankConstant int, rankWindowSize int, retrievers ...esearch.Retriever) *Builder {
+	b.rrf = &rrf{
+		param: retriever.RRFParam{
+			RankConstant:   rankConstant,
+			RankWindowSize: rankWindowSize,
+		},
+		retrievers: retrievers,
+	}
+
+	return b
+}
+
+// StandardRetriever 将 Builder 的查询条件转换成 standard 检索器
+func (b *Builder) StandardRetriever() *retriever.Standard {
+	return &retriever.Standard{
+		Query: b.pinnedQuery(b.scoreQuery(b.componentQuery())),
+	}
+}
+
+// KnnRetriever 生成 knn 检索器
+func (b *Builder) KnnRetriever(field string, vector []float32, k int, numCandidates int, filterFn NestWhereFunc) *retriever.Knn {
+	if numCandidates < k {
+		numCandidates = k
+	}
+
+	knn := &retriever.Knn{
+		Knn: esearch.Knn{
+			Field:         field,
+			QueryVector:   vector,
+			K:             k,
+			NumCandidates: numCandidates,
+		},
+	}
+
+	if filterFn != nil {
+		knn.Filter = b.subQuery(filterFn)
+	}
+
+	return knn
+}
+
+func (b *Builder) componentRetriever() esearch.Retriever {
+	if b.retriever != nil {
+		return b.retriever
+	}
+
+	if b.rrf == nil {
+		return nil
+	}
+
+	retrievers := make([]esearch.Retriever, 0, len(b.knn)+len(b.rrf.retrievers)+1)
+	if b.hasQuery() {
+		retrievers = append(retrievers, b.StandardRetriever())
+	}
+
+	for _, knn := range b.knn {
+		retrievers = append(retrievers, &retriever.Knn{Knn: knn})
+	}
+
+	retrievers = append(retrievers, b.rrf.retrievers...)
+
+	return &retriever.RRF{
+		Retrievers: retrievers,
+		RRFParam:   b.rrf.param,
+	}
+}
+
+// checkRetriever 设置检索器时 es 不允许顶层的 sort, OrderBy 等排序不能与 Retriever, RRF 同时使用
+func (b *Builder) checkRetriever() error {
+	if len(b.sort) > 0 && (b.retriever != nil || b.rrf != nil) {
+		return errors.New("sort can not be used with retriever")
+	}
+
+	return nil
+}
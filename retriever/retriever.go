@@ -0,0 +1,67 @@
+package retriever
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+// Standard standard 检索器, 使用普通的查询语句
+type Standard struct {
+	Query    esearch.Query `json:"query,omitempty"`
+	Filter   esearch.Query `json:"filter,omitempty"`
+	MinScore float64       `json:"min_score,omitempty"`
+}
+
+func (s *Standard) MarshalJSON() ([]byte, error) {
+	type standard Standard
+	return wrap("standard", (*standard)(s))
+}
+
+func (s *Standard) Retrieve() {
+
+}
+
+// Knn knn 检索器, 使用向量检索
+type Knn struct {
+	esearch.Knn
+}
+
+func (k *Knn) MarshalJSON() ([]byte, error) {
+	// knn 检索器不支持 boost, 得分权重由外层检索器决定
+	knn := k.Knn
+	knn.Boost = 0
+	return wrap("knn", knn)
+}
+
+func (k *Knn) Retrieve() {
+
+}
+
+// RRF rrf 检索器, 使用倒数排序融合(Reciprocal Rank Fusion)合并多个检索器的结果, 至少需要 2 个检索器
+type RRF struct {
+	Retrievers []esearch.Retriever `json:"retrievers"`
+	RRFParam
+}
+
+type RRFParam struct {
+	RankConstant   int `json:"rank_constant,omitempty"`
+	RankWindowSize int `json:"rank_window_size,omitempty"`
+}
+
+func (r *RRF) MarshalJSON() ([]byte, error) {
+	if len(r.Retrievers) < 2 {
+		return nil, errors.New("rrf retriever requires at least 2 retrievers")
+	}
+
+	type rrf RRF
+	return wrap("rrf", (*rrf)(r))
+}
+
+func (r *RRF) Retrieve() {
+
+}
+
+func wrap(name string, value any) ([]byte, error) {
+	return json.Marshal(map[string]any{name: value})
+}
@@ -0,0 +1,67 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+func TestRetriever(t *testing.T) {
+	boosted := func() esearch.KnnParam {
+		return esearch.KnnParam{Boost: 2}
+	}
+
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "rrf of query and knn",
+			b:    NewBuilder().Where("a", 1).Knn("vec", []float32{0.1}, 3, 3, nil).RRF(60, 100),
+			want: `{"retriever":{"rrf":{"retrievers":[{"standard":{"query":{"bool":{"must":[{"term":{"a":1}}]}}}},{"knn":{"field":"vec","query_vector":[0.1],"k":3,"num_candidates":3}}],"rank_constant":60,"rank_window_size":100}}}`,
+		},
+		{
+			name: "knn retriever drops boost",
+			b:    NewBuilder().Where("a", 1).KnnParams("vec", []float32{0.1}, 3, 3, nil, boosted).RRF(0, 0),
+			want: `{"retriever":{"rrf":{"retrievers":[{"standard":{"query":{"bool":{"must":[{"term":{"a":1}}]}}}},{"knn":{"field":"vec","query_vector":[0.1],"k":3,"num_candidates":3}}]}}}`,
+		},
+		{
+			name: "custom retriever",
+			b:    NewBuilder().Retriever(NewBuilder().KnnRetriever("vec", []float32{1}, 2, 1, nil)),
+			want: `{"retriever":{"knn":{"field":"vec","query_vector":[1],"k":2,"num_candidates":2}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
+
+func TestRetrieverError(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+	}{
+		{name: "rrf with query only", b: NewBuilder().Where("a", 1).RRF(0, 0)},
+		{name: "rrf with knn only", b: NewBuilder().Knn("vec", []float32{0.1}, 3, 3, nil).RRF(0, 0)},
+		{
+			name: "rrf with sort",
+			b:    NewBuilder().Where("a", 1).Knn("vec", []float32{0.1}, 3, 3, nil).OrderBy("date", esearch.Desc).RRF(0, 0),
+		},
+		{
+			name: "custom retriever with sort",
+			b:    NewBuilder().OrderBy("date", esearch.Asc).Retriever(NewBuilder().KnnRetriever("vec", []float32{1}, 2, 1, nil)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.b.Marshal(); err == nil {
+				t.Errorf("Marshal() expected error")
+			}
+		})
+	}
+}
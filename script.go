@@ -0,0 +1,64 @@
+package elastic
+
+import (
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"github.com/KingSolvewer/elasticsearch-query-builder/specialized"
+)
+
+// WhereScript Must script 查询, 使用脚本过滤文档, 脚本中的变量通过 params 传递
+func WhereScript(script esearch.Script) *Builder {
+	return builder.WhereScript(script)
+}
+
+// WhereScript Must script 查询, 使用脚本过滤文档, 脚本中的变量通过 params 传递
+func (b *Builder) WhereScript(script esearch.Script) *Builder {
+	b.scriptQuery(esearch.Must, script)
+
+	return b
+}
+
+// WhereNotScript MustNot script 查询, 使用脚本过滤文档, 脚本中的变量通过 params 传递
+func WhereNotScript(script esearch.Script) *Builder {
+	return builder.WhereNotScript(script)
+}
+
+// WhereNotScript MustNot script 查询, 使用脚本过滤文档, 脚本中的变量通过 params 传递
+func (b *Builder) WhereNotScript(script esearch.Script) *Builder {
+	b.scriptQuery(esearch.MustNot, script)
+
+	return b
+}
+
+// OrWhereScript Should script 查询, 使用脚本过滤文档, 脚本中的变量通过 params 传递
+func OrWhereScript(script esearch.Script) *Builder {
+	return builder.OrWhereScript(script)
+}
+
+// OrWhereScript Should script 查询, 使用脚本过滤文档, 脚本中的变量通过 params 传递
+func (b *Builder) OrWhereScript(script esearch.Script) *Builder {
+	b.scriptQuery(esearch.Should, script)
+
+	return b
+}
+
+// FilterScript Filter script 查询, 使用脚本过滤文档, 脚本中的变量通过 params 传递
+func FilterScript(script esearch.Script) *Builder {
+	return builder.FilterScript(script)
+}
+
+// FilterScript Filter script 查询, 使用脚本过滤文档, 脚本中的变量通过 params 传递
+func (b *Builder) FilterScript(script esearch.Script) *Builder {
+	b.scriptQuery(esearch.FilterClause, script)
+
+	return b
+}
+
+func (b *Builder) scriptQuery(clauseTyp esearch.BoolClauseType, script esearch.Script) {
+	if script.Source == "" && script.Id == "" {
+		return
+	}
+
+	b.append(clauseTyp, specialized.ScriptQuery{
+		Script: specialized.ScriptQueryBody{Script: script},
+	})
+}
@@ -0,0 +1,55 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/aggs"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+func TestScript(t *testing.T) {
+	script := esearch.Script{Source: "doc['a'].value * params.f", Params: map[string]any{"f": 2}}
+
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "inline script query",
+			b:    NewBuilder().WhereScript(script),
+			want: `{"query":{"bool":{"must":[{"script":{"script":{"source":"doc['a'].value * params.f","params":{"f":2}}}}]}}}`,
+		},
+		{
+			name: "stored script query",
+			b:    NewBuilder().FilterScript(esearch.Script{Id: "my"}),
+			want: `{"query":{"bool":{"filter":[{"script":{"script":{"id":"my"}}}]}}}`,
+		},
+		{
+			name: "empty script is skipped",
+			b:    NewBuilder().WhereNotScript(esearch.Script{Lang: "painless"}),
+			want: `{"query":{"match_all":{}}}`,
+		},
+		{
+			name: "script sort defaults",
+			b:    NewBuilder().OrderByScript(script, "", ""),
+			want: `{"sort":[{"_script":{"type":"number","script":{"source":"doc['a'].value * params.f","params":{"f":2}},"order":"asc"}}],"query":{"match_all":{}}}`,
+		},
+		{
+			name: "script sort string desc",
+			b:    NewBuilder().OrderByScript(esearch.Script{Id: "my"}, esearch.ScriptSortString, esearch.Desc),
+			want: `{"sort":[{"_script":{"type":"string","script":{"id":"my"},"order":"desc"}}],"query":{"match_all":{}}}`,
+		},
+		{
+			name: "metric aggregation script",
+			b:    NewBuilder().Avg("", aggs.MetricParam{Script: &script}),
+			want: `{"query":{"match_all":{}},"aggs":{"_avg":{"avg":{"script":{"source":"doc['a'].value * params.f","params":{"f":2}}}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package elastic
+
+import (
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+	"github.com/KingSolvewer/elasticsearch-query-builder/intervals"
+	"github.com/KingSolvewer/elasticsearch-query-builder/span"
+)
+
+// WhereSpan Must span 查询, 例如 span.Near, span.Or, span.Not 等
+func WhereSpan(clause span.Query) *Builder {
+	return builder.WhereSpan(clause)
+}
+
+// WhereSpan Must span 查询, 例如 span.Near, span.Or, span.Not 等
+func (b *Builder) WhereSpan(clause span.Query) *Builder {
+	if clause != nil {
+		b.append(esearch.Must, clause)
+	}
+
+	return b
+}
+
+// WhereNotSpan MustNot span 查询, 例如 span.Near, span.Or, span.Not 等
+func WhereNotSpan(clause span.Query) *Builder {
+	return builder.WhereNotSpan(clause)
+}
+
+// WhereNotSpan MustNot span 查询, 例如 span.Near, span.Or, span.Not 等
+func (b *Builder) WhereNotSpan(clause span.Query) *Builder {
+	if clause != nil {
+		b.append(esearch.MustNot, clause)
+	}
+
+	return b
+}
+
+// OrWhereSpan Should span 查询, 例如 span.Near, span.Or, span.Not 等
+func OrWhereSpan(clause span.Query) *Builder {
+	return builder.OrWhereSpan(clause)
+}
+
+// OrWhereSpan Should span 查询, 例如 span.Near, span.Or, span.Not 等
+func (b *Builder) OrWhereSpan(clause span.Query) *Builder {
+	if clause != nil {
+		b.append(esearch.Should, clause)
+	}
+
+	return b
+}
+
+// FilterSpan Filter span 查询, 例如 span.Near, span.Or, span.Not 等
+func FilterSpan(clause span.Query) *Builder {
+	return builder.FilterSpan(clause)
+}
+
+// FilterSpan Filter span 查询, 例如 span.Near, span.Or, span.Not 等
+func (b *Builder) FilterSpan(clause span.Query) *Builder {
+	if clause != nil {
+		b.append(esearch.FilterClause, clause)
+	}
+
+	return b
+}
+
+// WhereIntervals Must intervals 查询, 例如 intervals.Match, intervals.AllOf 等
+func WhereIntervals(field string, rule intervals.Rule) *Builder {
+	return builder.WhereIntervals(field, rule)
+}
+
+// WhereIntervals Must intervals 查询, 例如 intervals.Match, intervals.AllOf 等
+func (b *Builder) WhereIntervals(field string, rule intervals.Rule) *Builder {
+	b.intervals(esearch.Must, field, rule)
+
+	return b
+}
+
+// WhereNotIntervals MustNot intervals 查询, 例如 intervals.Match, intervals.AllOf 等
+func WhereNotIntervals(field string, rule intervals.Rule) *Builder {
+	return builder.WhereNotIntervals(field, rule)
+}
+
+// WhereNotIntervals MustNot intervals 查询, 例如 intervals.Match, intervals.AllOf 等
+func (b *Builder) WhereNotIntervals(field string, rule intervals.Rule) *Builder {
+	b.intervals(esearch.MustNot, field, rule)
+
+	return b
+}
+
+// OrWhereIntervals Should intervals 查询, 例如 intervals.Match, intervals.AllOf 等
+func OrWhereIntervals(field string, rule intervals.Rule) *Builder {
+	return builder.OrWhereIntervals(field, rule)
+}
+
+// OrWhereIntervals Should intervals 查询, 例如 intervals.Match, intervals.AllOf 等
+func (b *Builder) OrWhereIntervals(field string, rule intervals.Rule) *Builder {
+	b.intervals(esearch.Should, field, rule)
+
+	return b
+}
+
+// FilterIntervals Filter intervals 查询, 例如 intervals.Match, intervals.AllOf 等
+func FilterIntervals(field string, rule intervals.Rule) *Builder {
+	return builder.FilterIntervals(field, rule)
+}
+
+// FilterIntervals Filter intervals 查询, 例如 intervals.Match, intervals.AllOf 等
+func (b *Builder) FilterIntervals(field string, rule intervals.Rule) *Builder {
+	b.intervals(esearch.FilterClause, field, rule)
+
+	return b
+}
+
+func (b *Builder) intervals(clauseTyp esearch.BoolClauseType, field string, rule intervals.Rule) {
+	if rule == nil {
+		return
+	}
+
+	query := intervals.Query{
+		Intervals: make(map[string]intervals.Rule),
+	}
+	query.Intervals[field] = rule
+
+	b.append(clauseTyp, query)
+}
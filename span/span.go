@@ -0,0 +1,196 @@
+package span
+
+import (
+	"encoding/json"
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+// Query span 查询子句, 可以相互嵌套组合, 也可以直接作为 bool 查询的子句
+type Query interface {
+	esearch.BoolBuilder
+	Span()
+}
+
+// Term span_term 查询
+type Term struct {
+	Field string
+	Value string
+	Boost float32
+}
+
+func (t Term) MarshalJSON() ([]byte, error) {
+	term := make(map[string]any)
+	if t.Boost != 0 {
+		term[t.Field] = map[string]any{"value": t.Value, "boost": t.Boost}
+	} else {
+		term[t.Field] = t.Value
+	}
+
+	return wrap("span_term", term)
+}
+
+// Near span_near 查询, 子句之间最多间隔 Slop 个词, InOrder 为 true 时, 子句必须按顺序出现
+type Near struct {
+	Clauses []Query `json:"clauses"`
+	Slop    int     `json:"slop"`
+	InOrder bool    `json:"in_order"`
+}
+
+func (n Near) MarshalJSON() ([]byte, error) {
+	type near Near
+	return wrap("span_near", near(n))
+}
+
+// Or span_or 查询, 匹配任意一个子句
+type Or struct {
+	Clauses []Query `json:"clauses"`
+}
+
+func (o Or) MarshalJSON() ([]byte, error) {
+	type or Or
+	return wrap("span_or", or(o))
+}
+
+// Not span_not 查询, 匹配 Include 并且不与 Exclude 重叠的部分
+type Not struct {
+	Include Query `json:"include"`
+	Exclude Query `json:"exclude"`
+	Pre     int   `json:"pre,omitempty"`
+	Post    int   `json:"post,omitempty"`
+	Dist    int   `json:"dist,omitempty"`
+}
+
+func (n Not) MarshalJSON() ([]byte, error) {
+	type not Not
+	return wrap("span_not", not(n))
+}
+
+// First span_first 查询, 匹配出现在字段前 End 个位置中的 Match
+type First struct {
+	Match Query `json:"match"`
+	End   int   `json:"end"`
+}
+
+func (f First) MarshalJSON() ([]byte, error) {
+	type first First
+	return wrap("span_first", first(f))
+}
+
+// Multi span_multi 查询, 包装 prefix, wildcard, regexp, fuzzy, range 等 term 级别的查询, 例如 termlevel.TermQuery
+type Multi struct {
+	Match esearch.BoolBuilder `json:"match"`
+}
+
+func (m Multi) MarshalJSON() ([]byte, error) {
+	type multi Multi
+	return wrap("span_multi", multi(m))
+}
+
+// Containing span_containing 查询, 返回包含 Little 的 Big
+type Containing struct {
+	Big    Query `json:"big"`
+	Little Query `json:"little"`
+}
+
+func (c Containing) MarshalJSON() ([]byte, error) {
+	type containing Containing
+	return wrap("span_containing", containing(c))
+}
+
+// Within span_within 查询, 返回被 Big 包含的 Little
+type Within struct {
+	Big    Query `json:"big"`
+	Little Query `json:"little"`
+}
+
+func (w Within) MarshalJSON() ([]byte, error) {
+	type within Within
+	return wrap("span_within", within(w))
+}
+
+// FieldMasking field_masking_span 查询, 使不同字段上的 span 查询可以组合在一起
+type FieldMasking struct {
+	Query Query  `json:"query"`
+	Field string `json:"field"`
+}
+
+func (f FieldMasking) MarshalJSON() ([]byte, error) {
+	type fieldMasking FieldMasking
+	return wrap("field_masking_span", fieldMasking(f))
+}
+
+func (t Term) Span() {
+
+}
+
+func (n Near) Span() {
+
+}
+
+func (o Or) Span() {
+
+}
+
+func (n Not) Span() {
+
+}
+
+func (f First) Span() {
+
+}
+
+func (m Multi) Span() {
+
+}
+
+func (c Containing) Span() {
+
+}
+
+func (w Within) Span() {
+
+}
+
+func (f FieldMasking) Span() {
+
+}
+
+func (t Term) BoolBuild() string {
+	return ""
+}
+
+func (n Near) BoolBuild() string {
+	return ""
+}
+
+func (o Or) BoolBuild() string {
+	return ""
+}
+
+func (n Not) BoolBuild() string {
+	return ""
+}
+
+func (f First) BoolBuild() string {
+	return ""
+}
+
+func (m Multi) BoolBuild() string {
+	return ""
+}
+
+func (c Containing) BoolBuild() string {
+	return ""
+}
+
+func (w Within) BoolBuild() string {
+	return ""
+}
+
+func (f FieldMasking) BoolBuild() string {
+	return ""
+}
+
+func wrap(name string, value any) ([]byte, error) {
+	return json.Marshal(map[string]any{name: value})
+}
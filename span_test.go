@@ -0,0 +1,54 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/KingSolvewer/elasticsearch-query-builder/intervals"
+	"github.com/KingSolvewer/elasticsearch-query-builder/span"
+)
+
+func TestSpanAndIntervals(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{
+			name: "span_near",
+			b: NewBuilder().WhereSpan(span.Near{
+				Clauses: []span.Query{span.Term{Field: "title", Value: "中国"}, span.Term{Field: "title", Value: "电信", Boost: 2}},
+				Slop:    1,
+				InOrder: true,
+			}),
+			want: `{"query":{"bool":{"must":[{"span_near":{"clauses":[{"span_term":{"title":"中国"}},{"span_term":{"title":{"boost":2,"value":"电信"}}}],"slop":1,"in_order":true}}]}}}`,
+		},
+		{
+			name: "span_first",
+			b:    NewBuilder().FilterSpan(span.First{Match: span.Term{Field: "title", Value: "a"}, End: 3}),
+			want: `{"query":{"bool":{"filter":[{"span_first":{"match":{"span_term":{"title":"a"}},"end":3}}]}}}`,
+		},
+		{
+			name: "intervals all_of with any_of",
+			b: NewBuilder().WhereIntervals("content", intervals.AllOf{
+				Ordered: true,
+				MaxGaps: intervals.Gaps(0),
+				Intervals: []intervals.Rule{
+					intervals.Match{Query: "my favorite"},
+					intervals.AnyOf{Intervals: []intervals.Rule{intervals.Match{Query: "hot water"}, intervals.Prefix{Prefix: "cold"}}},
+				},
+			}),
+			want: `{"query":{"bool":{"must":[{"intervals":{"content":{"all_of":{"intervals":[{"match":{"query":"my favorite"}},{"any_of":{"intervals":[{"match":{"query":"hot water"}},{"prefix":{"prefix":"cold"}}]}}],"max_gaps":0,"ordered":true}}}}]}}}`,
+		},
+		{
+			name: "intervals without rule is skipped",
+			b:    NewBuilder().WhereIntervals("content", nil),
+			want: `{"query":{"match_all":{}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDsl(t, tt.b, tt.want)
+		})
+	}
+}
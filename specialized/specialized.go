@@ -0,0 +1,121 @@
+package specialized
+
+import (
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
+type PercolateQuery struct {
+	Percolate Percolate `json:"percolate"`
+}
+
+// Percolate percolate 查询, 查询与文档匹配的已存储的查询语句. Document 和 Documents 为待匹配的文档, PercolateRef 引用已索引的文档
+type Percolate struct {
+	Field     string `json:"field"`
+	Name      string `json:"name,omitempty"`
+	Document  any    `json:"document,omitempty"`
+	Documents []any  `json:"documents,omitempty"`
+	*PercolateRef
+}
+
+type PercolateRef struct {
+	Index      string `json:"index"`
+	Id         string `json:"id"`
+	Routing    string `json:"routing,omitempty"`
+	Preference string `json:"preference,omitempty"`
+	Version    int64  `json:"version,omitempty"`
+}
+
+func (percolate PercolateQuery) BoolBuild() string {
+	return ""
+}
+
+// ScriptQuery script 查询, 使用脚本过滤文档
+type ScriptQuery struct {
+	Script ScriptQueryBody `json:"script"`
+}
+
+type ScriptQueryBody struct {
+	Script esearch.Script `json:"script"`
+	Boost  float32        `json:"boost,omitempty"`
+}
+
+func (script ScriptQuery) BoolBuild() string {
+	return ""
+}
+
+// RankFeatureQuery rank_feature 查询, 根据 rank_feature, rank_features 类型字段的值提高得分
+type RankFeatureQuery struct {
+	RankFeature RankFeature `json:"rank_feature"`
+}
+
+type RankFeature struct {
+	Field string `json:"field"`
+	RankFeatureParam
+}
+
+type RankFeatureParamFunc func() RankFeatureParam
+
+// RankFeatureParam Saturation, Log, Sigmoid, Linear 只能设置一个, 都不设置时 es 默认使用 saturation
+type RankFeatureParam struct {
+	Saturation *Saturation `json:"saturation,omitempty"`
+	Log        *Log        `json:"log,omitempty"`
+	Sigmoid    *Sigmoid    `json:"sigmoid,omitempty"`
+	Linear     *Linear     `json:"linear,omitempty"`
+	Boost      float32     `json:"boost,omitempty"`
+}
+
+type Saturation struct {
+	Pivot float64 `json:"pivot,omitempty"`
+}
+
+type Log struct {
+	ScalingFactor float64 `json:"scaling_factor"`
+}
+
+type Sigmoid struct {
+	Pivot    float64 `json:"pivot"`
+	Exponent float64 `json:"exponent"`
+}
+
+type Linear struct {
+}
+
+func (rank RankFeatureQuery) BoolBuild() string {
+	return ""
+}
+
+// DistanceFeatureQuery distance_feature 查询, 距离 origin 越近得分越高, 支持 date, date_nanos, geo_point 类型的字段
+type DistanceFeatureQuery struct {
+	DistanceFeature DistanceFeature `json:"distance_feature"`
+}
+
+type DistanceFeature struct {
+	Field  string  `json:"field"`
+	Origin any     `json:"origin"`
+	Pivot  string  `json:"pivot"`
+	Boost  float32 `json:"boost,omitempty"`
+}
+
+func (distance DistanceFeatureQuery) BoolBuild() string {
+	return ""
+}
+
+// Pinned pinned 查询, 将指定的文档置顶, 其余文档按照 organic 查询排序. Ids 和 Docs 二选一
+type Pinned struct {
+	Ids     []string      `json:"ids,omitempty"`
+	Docs    []PinnedDoc   `json:"docs,omitempty"`
+	Organic esearch.Query `json:"organic"`
+}
+
+type PinnedDoc struct {
+	Index string `json:"_index,omitempty"`
+	Id    string `json:"_id"`
+}
+
+func (pinned *Pinned) QueryBuild() string {
+	return ""
+}
+
+func (pinned *Pinned) BoolBuild() string {
+	return ""
+}
@@ -1,13 +1,21 @@
 package termlevel
 
+import (
+	"github.com/KingSolvewer/elasticsearch-query-builder/esearch"
+)
+
 type TermQuery struct {
-	Term     map[string]any        `json:"term,omitempty"`
-	Prefix   map[string]any        `json:"prefix,omitempty"`
-	Range    map[string]RangeQuery `json:"range,omitempty"`
-	Terms    map[string][]any      `json:"terms,omitempty"`
-	Exists   map[string]string     `json:"exists,omitempty"`
-	Regexp   map[string]Regexp     `json:"regexp,omitempty"`
-	Wildcard map[string]Wildcard   `json:"wildcard,omitempty"`
+	Term      map[string]any        `json:"term,omitempty"`
+	Prefix    map[string]any        `json:"prefix,omitempty"`
+	Range     map[string]RangeQuery `json:"range,omitempty"`
+	Terms     map[string][]any      `json:"terms,omitempty"`
+	Exists    map[string]string     `json:"exists,omitempty"`
+	Regexp    map[string]Regexp     `json:"regexp,omitempty"`
+	Wildcard  map[string]Wildcard   `json:"wildcard,omitempty"`
+	Fuzzy     map[string]Fuzzy      `json:"fuzzy,omitempty"`
+	Ids       *Ids                  `json:"ids,omitempty"`
+	TermsSet  map[string]TermsSet   `json:"terms_set,omitempty"`
+	MatchNone *MatchNone            `json:"match_none,omitempty"`
 }
 
 type RangeQuery struct {
@@ -43,6 +51,70 @@ type WildcardParam struct {
 	Wildcard string  `json:"wildcard,omitempty"`
 }
 
+type Prefix struct {
+	Value string `json:"value"`
+	PrefixParam
+}
+
+type PrefixParamFunc func() PrefixParam
+
+type PrefixParam struct {
+	CaseInsensitive bool   `json:"case_insensitive,omitempty"`
+	Rewrite         string `json:"rewrite,omitempty"`
+}
+
+type Fuzzy struct {
+	Value string `json:"value"`
+	FuzzyParam
+}
+
+type FuzzyParamFunc func() FuzzyParam
+
+type FuzzyParam struct {
+	Fuzziness      string `json:"fuzziness,omitempty"` // AUTO, 0, 1, 2
+	MaxExpansions  int    `json:"max_expansions,omitempty"`
+	PrefixLength   int    `json:"prefix_length,omitempty"`
+	Transpositions *bool  `json:"transpositions,omitempty"` // es 默认为 true, 需要禁用时设置为 false
+	Rewrite        string `json:"rewrite,omitempty"`
+}
+
+type Ids struct {
+	Values []string `json:"values"`
+}
+
+type TermsSet struct {
+	Terms []any `json:"terms"`
+	TermsSetParam
+}
+
+type TermsSetParamFunc func() TermsSetParam
+
+// TermsSetParam MinimumShouldMatchField 和 MinimumShouldMatchScript 二选一
+type TermsSetParam struct {
+	MinimumShouldMatchField  string          `json:"minimum_should_match_field,omitempty"`
+	MinimumShouldMatchScript *esearch.Script `json:"minimum_should_match_script,omitempty"`
+	Boost                    float32         `json:"boost,omitempty"`
+}
+
+type MatchNone struct {
+}
+
+// TermsLookupQuery terms 查询, 查询的值从其他索引的文档中获取
+type TermsLookupQuery struct {
+	Terms map[string]TermsLookup `json:"terms"`
+}
+
+type TermsLookup struct {
+	Index   string `json:"index"`
+	Id      string `json:"id"`
+	Path    string `json:"path"`
+	Routing string `json:"routing,omitempty"`
+}
+
+func (lookup TermsLookupQuery) BoolBuild() string {
+	return ""
+}
+
 func (term TermQuery) BoolBuild() string {
 	return ""
 }
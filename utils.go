@@ -4,6 +4,8 @@ import (
 	"errors"
 	"github.com/KingSolvewer/elasticsearch-query-builder/aggs"
 	"reflect"
+	"regexp"
+	"strings"
 )
 
 func CheckHitsDestType(dest any) error {
@@ -54,6 +56,68 @@ func checkType(value any) bool {
 	}
 }
 
+var calendarIntervals = map[string]bool{
+	"second": true, "1s": true,
+	"minute": true, "1m": true,
+	"hour": true, "1h": true,
+	"day": true, "1d": true,
+	"week": true, "1w": true,
+	"month": true, "1M": true,
+	"quarter": true, "1q": true,
+	"year": true, "1y": true,
+}
+
+var autoDateMinimumIntervals = map[string]bool{
+	"year":   true,
+	"month":  true,
+	"day":    true,
+	"hour":   true,
+	"minute": true,
+	"second": true,
+}
+
+var fixedIntervalRegexp = regexp.MustCompile(`^[1-9][0-9]*(nanos|micros|ms|s|m|h|d)$`)
+
+// checkDateInterval interval, calendar_interval, fixed_interval 最多设置一个, 都不设置时与之前一样不校验. 日历间隔的单词形式不区分大小写,
+// 会转换成小写, 1m(分钟) 和 1M(月) 区分大小写
+func checkDateInterval(param *aggs.HistogramParam) bool {
+	count := 0
+	if param.Interval != nil {
+		count++
+	}
+
+	if param.CalendarInterval != "" {
+		interval, ok := calendarInterval(param.CalendarInterval)
+		if !ok {
+			return false
+		}
+		param.CalendarInterval = interval
+		count++
+	}
+
+	if param.FixedInterval != "" {
+		if !fixedIntervalRegexp.MatchString(param.FixedInterval) {
+			return false
+		}
+		count++
+	}
+
+	return count <= 1
+}
+
+func calendarInterval(interval string) (string, bool) {
+	if calendarIntervals[interval] {
+		return interval, true
+	}
+
+	lower := strings.ToLower(interval)
+	if lower[0] >= '0' && lower[0] <= '9' {
+		return "", false
+	}
+
+	return lower, calendarIntervals[lower]
+}
+
 func checkAggsRangeType(ranges []aggs.Ranges) bool {
 	var check bool
 	for _, r := range ranges {